@@ -1,11 +1,15 @@
 package app
 
 import (
+	"errors"
 	"path/filepath"
 	"regexp"
 )
 
 // Find searches existing (running) app by its executable file path.
+// exePath is a regular expression matched against the base name of the
+// executable, or the exact base name when it is not a valid expression. Use
+// Query for more conditions and for errors of invalid expressions.
 func Find(exePath string) []*App {
 	r, _ := regexp.Compile(exePath)
 	pids := ps()
@@ -53,3 +57,16 @@ func (app *App) Menu() *Menu {
 func (app *App) Windows() []*Window {
 	return app.windows()
 }
+
+// errNoFocus is returned when no window has the keyboard focus, e.g. while the desktop is focused.
+var errNoFocus = errors.New("app: no window has focus")
+
+// Frontmost returns the app owning the window which has the keyboard focus.
+func Frontmost() (*App, error) {
+	return frontmost()
+}
+
+// FocusedWindow returns the window which has the keyboard focus.
+func FocusedWindow() (*Window, error) {
+	return focusedWindow()
+}
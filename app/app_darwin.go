@@ -1,12 +1,49 @@
 package app
 
-// #cgo LDFLAGS: -framework ApplicationServices
-// #include <libproc.h>
-// #include <ApplicationServices/ApplicationServices.h>
+/*
+#cgo LDFLAGS: -framework ApplicationServices
+#include <libproc.h>
+#include <sys/sysctl.h>
+#include <ApplicationServices/ApplicationServices.h>
+
+static int bsd_info(pid_t pid, pid_t *ppid, uid_t *uid) {
+	struct proc_bsdshortinfo info;
+	if (proc_pidinfo(pid, PROC_PIDT_SHORTBSDINFO, 0, &info, sizeof(info)) != sizeof(info)) {
+		return 0;
+	}
+	*ppid = info.pbsi_ppid;
+	*uid = info.pbsi_uid;
+	return 1;
+}
+
+// procargs reads KERN_PROCARGS2 of pid into buf, and returns its length or -1.
+static int procargs(pid_t pid, char *buf, size_t size) {
+	int mib[3] = { CTL_KERN, KERN_PROCARGS2, pid };
+	if (sysctl(mib, 3, buf, &size, NULL, 0) != 0) {
+		return -1;
+	}
+	return (int)size;
+}
+
+static int argmax() {
+	int mib[2] = { CTL_KERN, KERN_ARGMAX };
+	int max = 0;
+	size_t size = sizeof(max);
+	if (sysctl(mib, 2, &max, &size, NULL, 0) != 0) {
+		return 0;
+	}
+	return max;
+}
+*/
 import "C"
 
 import (
+	"bytes"
+	"encoding/binary"
 	"github.com/kbinani/robot/ax"
+	"os/user"
+	"strconv"
+	"syscall"
 	"unsafe"
 )
 
@@ -15,6 +52,8 @@ type PID C.pid_t
 type App struct {
 	pid   PID
 	axApp *ax.UIElement
+	// proc is the process of an app started by Launch.
+	proc *process
 }
 
 func newApp(pid PID) *App {
@@ -63,3 +102,98 @@ func ps() []C.pid_t {
 	}
 	return result
 }
+
+func frontmost() (*App, error) {
+	focused := ax.CreateSystemWide().FocusedApplication()
+	if focused == nil {
+		return nil, errNoFocus
+	}
+	return newApp(PID(focused.PID())), nil
+}
+
+func focusedWindow() (*Window, error) {
+	focused := ax.CreateSystemWide().FocusedApplication()
+	if focused == nil {
+		return nil, errNoFocus
+	}
+	w := focused.FocusedWindow()
+	if w == nil {
+		return nil, errNoFocus
+	}
+	return newWindow(w), nil
+}
+
+// allWindows returns the windows of every app which the accessibility API can see.
+func allWindows() []*Window {
+	ret := []*Window{}
+	for _, pid := range ps() {
+		ret = append(ret, newApp(PID(pid)).windows()...)
+	}
+	return ret
+}
+
+func isRunning(pid PID) bool {
+	// Signal 0 checks for existence; EPERM means the process exists but belongs to another user.
+	err := syscall.Kill(int(pid), 0)
+	return err == nil || err == syscall.EPERM
+}
+
+func parentPIDs() map[PID]PID {
+	parents := map[PID]PID{}
+	for _, pid := range ps() {
+		var ppid C.pid_t
+		var uid C.uid_t
+		if C.bsd_info(pid, &ppid, &uid) != 0 {
+			parents[PID(pid)] = PID(ppid)
+		}
+	}
+	return parents
+}
+
+func processUser(pid PID) (name, id string, ok bool) {
+	var ppid C.pid_t
+	var uid C.uid_t
+	if C.bsd_info(C.pid_t(pid), &ppid, &uid) == 0 {
+		return "", "", false
+	}
+	id = strconv.Itoa(int(uid))
+	if u, err := user.LookupId(id); err == nil {
+		name = u.Username
+	}
+	return name, id, true
+}
+
+// cmdline returns the arguments of pid from KERN_PROCARGS2, which is only readable for processes of the same user.
+func cmdline(pid PID) []string {
+	size := C.argmax()
+	if size <= 0 {
+		return nil
+	}
+	buf := make([]byte, int(size))
+	n := C.procargs(C.pid_t(pid), (*C.char)(unsafe.Pointer(&buf[0])), C.size_t(len(buf)))
+	if n < 4 {
+		return nil
+	}
+	// The buffer holds argc, the executable path, NUL padding, and then the NUL terminated arguments.
+	buf = buf[:n]
+	argc := int(binary.LittleEndian.Uint32(buf))
+	buf = buf[4:]
+	i := bytes.IndexByte(buf, 0)
+	if i < 0 {
+		return nil
+	}
+	buf = bytes.TrimLeft(buf[i:], "\x00")
+	args := []string{}
+	for len(args) < argc && len(buf) > 0 {
+		i := bytes.IndexByte(buf, 0)
+		if i < 0 {
+			i = len(buf)
+		}
+		args = append(args, string(buf[:i]))
+		buf = buf[i:]
+		if len(buf) > 0 {
+			buf = buf[1:]
+		}
+	}
+	return args
+}
@@ -0,0 +1,154 @@
+package app
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+type PID int
+
+type App struct {
+	pid PID
+	// proc is the process of an app started by Launch.
+	proc *process
+}
+
+// ProcRoot is the directory where procfs is mounted. It can be changed to read a fake tree.
+var ProcRoot = "/proc"
+
+func newApp(pid PID) *App {
+	a := new(App)
+	a.pid = pid
+	return a
+}
+
+func (app *App) menu() *Menu {
+	return nil
+}
+
+func (app *App) path() string {
+	return pathFromPID(app.pid)
+}
+
+func (app *App) name() string {
+	comm, err := ioutil.ReadFile(procPath(app.pid, "comm"))
+	name := strings.TrimSpace(string(comm))
+	if err == nil && len(name) == maxCommLen {
+		// comm may have been truncated, so prefer the executable name when comm is a prefix of it.
+		if base := filepath.Base(pathFromPID(app.pid)); base != "." && base != "/" && strings.HasPrefix(base, name) {
+			return base
+		}
+	}
+	if err == nil && name != "" {
+		return name
+	}
+	if args := cmdline(app.pid); len(args) > 0 {
+		return filepath.Base(args[0])
+	}
+	return ""
+}
+
+// maxCommLen is the length of comm in procfs, which the kernel truncates command names to.
+const maxCommLen = 15
+
+func procPath(pid PID, elem ...string) string {
+	return filepath.Join(append([]string{ProcRoot, strconv.Itoa(int(pid))}, elem...)...)
+}
+
+// cmdline returns the command line arguments of pid, which is empty for kernel threads and zombies.
+func cmdline(pid PID) []string {
+	b, err := ioutil.ReadFile(procPath(pid, "cmdline"))
+	if err != nil {
+		return nil
+	}
+	b = bytes.TrimRight(b, "\x00")
+	if len(b) == 0 {
+		return nil
+	}
+	return strings.Split(string(b), "\x00")
+}
+
+// procStat returns the fields of /proc/<pid>/stat following the command name, starting with the state.
+func procStat(pid PID) ([]string, bool) {
+	b, err := ioutil.ReadFile(procPath(pid, "stat"))
+	if err != nil {
+		return nil, false
+	}
+	// The command name is enclosed in parentheses and may contain anything, including ") ".
+	i := bytes.LastIndexByte(b, ')')
+	if i < 0 {
+		return nil, false
+	}
+	return strings.Fields(string(b[i+1:])), true
+}
+
+func isRunning(pid PID) bool {
+	fields, ok := procStat(pid)
+	// Zombies have exited, and only wait for their parent to collect the status.
+	return ok && len(fields) > 0 && fields[0] != "Z" && fields[0] != "X"
+}
+
+func parentPIDs() map[PID]PID {
+	parents := map[PID]PID{}
+	for _, pid := range ps() {
+		fields, ok := procStat(pid)
+		// Zombies have exited, so they are no longer children of anything.
+		if !ok || len(fields) < 2 || fields[0] == "Z" || fields[0] == "X" {
+			continue
+		}
+		if ppid, err := strconv.Atoi(fields[1]); err == nil {
+			parents[pid] = PID(ppid)
+		}
+	}
+	return parents
+}
+
+// processUser returns the name and id of the user owning pid, which is the owner of its /proc directory.
+func processUser(pid PID) (name, id string, ok bool) {
+	info, err := os.Stat(procPath(pid))
+	if err != nil {
+		return "", "", false
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return "", "", false
+	}
+	id = strconv.Itoa(int(st.Uid))
+	if u, err := user.LookupId(id); err == nil {
+		name = u.Username
+	}
+	return name, id, true
+}
+
+func pathFromPID(pid PID) string {
+	if exe, err := os.Readlink(procPath(pid, "exe")); err == nil {
+		return strings.TrimSuffix(exe, " (deleted)")
+	}
+	// exe is not readable for processes of other users, so fall back to the command line.
+	if args := cmdline(pid); len(args) > 0 && filepath.IsAbs(args[0]) {
+		return args[0]
+	}
+	return ""
+}
+
+func ps() []PID {
+	pids := make([]PID, 0)
+	entries, err := ioutil.ReadDir(ProcRoot)
+	if err != nil {
+		return pids
+	}
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil || !e.IsDir() {
+			continue
+		}
+		pids = append(pids, PID(pid))
+	}
+	return pids
+}
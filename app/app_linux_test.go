@@ -0,0 +1,135 @@
+package app
+
+import (
+	"reflect"
+	"testing"
+)
+
+// useFakeProc makes the package read the fake procfs in testdata/proc until the test ends.
+func useFakeProc(t *testing.T) {
+	old := ProcRoot
+	ProcRoot = "testdata/proc"
+	t.Cleanup(func() { ProcRoot = old })
+}
+
+func pids(apps []*App) []PID {
+	result := []PID{}
+	for _, a := range apps {
+		result = append(result, a.PID())
+	}
+	return result
+}
+
+func TestPs(t *testing.T) {
+	useFakeProc(t)
+	want := []PID{1, 100, 200, 201, 300, 400}
+	got := ps()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestName(t *testing.T) {
+	useFakeProc(t)
+	tests := []struct {
+		pid  PID
+		want string
+	}{
+		{1, "systemd"},
+		// comm is truncated to 15 bytes.
+		{100, "gnome-terminal-server"},
+		// A short comm is complete, even when it is a prefix of the executable name.
+		{201, "python3"},
+		{300, "worker) (1"},
+		// Without comm, the command line is used.
+		{400, "tool"},
+	}
+	for _, tt := range tests {
+		if got := newApp(tt.pid).Name(); got != tt.want {
+			t.Errorf("Name of %d is %q, want %q", tt.pid, got, tt.want)
+		}
+	}
+}
+
+func TestPath(t *testing.T) {
+	useFakeProc(t)
+	tests := []struct {
+		pid  PID
+		want string
+	}{
+		{200, "/usr/bin/bash"},
+		{201, "/usr/bin/python3.11"},
+		// Without exe, an absolute program name from the command line is used.
+		{400, "/opt/tool/bin/tool"},
+		{300, ""},
+	}
+	for _, tt := range tests {
+		if got := newApp(tt.pid).Path(); got != tt.want {
+			t.Errorf("Path of %d is %q, want %q", tt.pid, got, tt.want)
+		}
+	}
+}
+
+func TestCmdline(t *testing.T) {
+	useFakeProc(t)
+	if got, want := newApp(201).Cmdline(), []string{"python3", "-m", "http.server", "8000"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := newApp(300).Cmdline(); len(got) != 0 {
+		t.Errorf("zombie has command line %q", got)
+	}
+}
+
+func TestParentAndChildren(t *testing.T) {
+	useFakeProc(t)
+	if p := newApp(201).Parent(); p == nil || p.PID() != 200 {
+		t.Errorf("Parent of 201 is %v, want 200", p)
+	}
+	if p := newApp(1).Parent(); p != nil {
+		t.Errorf("Parent of 1 is %v, want nil", p.PID())
+	}
+	if p := newApp(300).Parent(); p != nil {
+		t.Errorf("Parent of a zombie is %v, want nil", p.PID())
+	}
+	// The zombie 300 is not a running child.
+	if got, want := pids(newApp(200).Children()), []PID{201}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Children of 200 are %v, want %v", got, want)
+	}
+	if got, want := pids(newApp(1).Children()), []PID{100, 400}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Children of 1 are %v, want %v", got, want)
+	}
+}
+
+func TestIsRunning(t *testing.T) {
+	useFakeProc(t)
+	for pid, want := range map[PID]bool{1: true, 201: true, 300: false, 999: false} {
+		if got := isRunning(pid); got != want {
+			t.Errorf("isRunning(%d) = %v, want %v", pid, got, want)
+		}
+	}
+}
+
+func TestQueryFakeProc(t *testing.T) {
+	useFakeProc(t)
+	tests := []struct {
+		f    Filter
+		want []PID
+	}{
+		{Filter{NameRegex: `^python`}, []PID{201}},
+		{Filter{PathRegex: `^/usr/lib`}, []PID{1, 100}},
+		{Filter{CmdlineRegex: `http\.server`}, []PID{201}},
+		{Filter{ParentPID: 1}, []PID{100, 400}},
+	}
+	for _, tt := range tests {
+		apps, err := Query(tt.f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := pids(apps); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Query(%+v) = %v, want %v", tt.f, got, tt.want)
+		}
+	}
+	if _, err := Query(Filter{NameRegex: "("}); err == nil {
+		t.Error("invalid expression did not fail")
+	}
+}
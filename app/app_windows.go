@@ -13,6 +13,8 @@ type PID uint32
 
 type App struct {
 	pid PID
+	// proc is the process of an app started by Launch.
+	proc *process
 }
 
 func newApp(pid PID) *App {
@@ -42,6 +44,64 @@ func (app *App) mainWindow() *Window {
 	return newWindow(data.Handle)
 }
 
+func (app *App) windows() []*Window {
+	ret := []*Window{}
+	for _, hWnd := range topLevelWindows() {
+		var pid uint32
+		win.GetWindowThreadProcessId(hWnd, &pid)
+		if PID(pid) == app.pid && win.IsWindowVisible(hWnd) {
+			ret = append(ret, newWindow(hWnd))
+		}
+	}
+	return ret
+}
+
+// allWindows returns the visible top-level windows of every app.
+func allWindows() []*Window {
+	ret := []*Window{}
+	for _, hWnd := range topLevelWindows() {
+		if win.IsWindowVisible(hWnd) {
+			ret = append(ret, newWindow(hWnd))
+		}
+	}
+	return ret
+}
+
+// topLevelWindows returns all top-level windows in z-order, topmost first.
+func topLevelWindows() []win.HWND {
+	var handles []win.HWND
+	win.EnumWindows(appendWindowCallback, (uintptr)(unsafe.Pointer(&handles)))
+	return handles
+}
+
+// appendWindowCallback is created once, as the runtime allows a limited number of callbacks.
+var appendWindowCallback = syscall.NewCallback(appendWindow)
+
+// appendWindow appends hWnd to the []win.HWND at lParam.
+func appendWindow(hWnd win.HWND, lParam uintptr) uintptr {
+	handles := (*[]win.HWND)(unsafe.Pointer(lParam))
+	*handles = append(*handles, hWnd)
+	return win.TRUE
+}
+
+func frontmost() (*App, error) {
+	w, err := focusedWindow()
+	if err != nil {
+		return nil, err
+	}
+	var pid uint32
+	win.GetWindowThreadProcessId(w.hWnd, &pid)
+	return newApp(PID(pid)), nil
+}
+
+func focusedWindow() (*Window, error) {
+	hWnd, _, _ := procGetForegroundWindow.Call()
+	if hWnd == 0 {
+		return nil, errNoFocus
+	}
+	return newWindow(win.HWND(hWnd)), nil
+}
+
 type tagEnumWindowsCallback struct {
 	Pid    uint32
 	Handle win.HWND
@@ -191,6 +251,99 @@ func stringFromUnicode16(r []uint16) string {
 	return string(decoded[:n])
 }
 
+func isRunning(pid PID) bool {
+	h, _, err := procOpenProcess.Call(processQueryLimitedInformation, 0, uintptr(pid))
+	if h == 0 {
+		// Protected processes, such as csrss.exe, cannot be opened even for limited queries, but exist.
+		return err == syscall.ERROR_ACCESS_DENIED
+	}
+	defer win.CloseHandle(win.HANDLE(h))
+	var code uint32
+	if ret, _, _ := procGetExitCodeProcess.Call(h, uintptr(unsafe.Pointer(&code))); ret == 0 {
+		return false
+	}
+	return code == stillActive
+}
+
+func parentPIDs() map[PID]PID {
+	parents := map[PID]PID{}
+	snapshot, err := syscall.CreateToolhelp32Snapshot(syscall.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return parents
+	}
+	defer syscall.CloseHandle(snapshot)
+	var entry syscall.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+	for err = syscall.Process32First(snapshot, &entry); err == nil; err = syscall.Process32Next(snapshot, &entry) {
+		parents[PID(entry.ProcessID)] = PID(entry.ParentProcessID)
+	}
+	return parents
+}
+
+// processUser returns the account owning the token of pid as "DOMAIN\user", and its SID.
+func processUser(pid PID) (name, id string, ok bool) {
+	h, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return "", "", false
+	}
+	defer syscall.CloseHandle(h)
+	var token syscall.Token
+	if err := syscall.OpenProcessToken(h, syscall.TOKEN_QUERY, &token); err != nil {
+		return "", "", false
+	}
+	defer token.Close()
+	tu, err := token.GetTokenUser()
+	if err != nil {
+		return "", "", false
+	}
+	id, _ = tu.User.Sid.String()
+	account, domain, _, err := tu.User.Sid.LookupAccount("")
+	if err == nil {
+		name = domain + `\` + account
+	}
+	return name, id, true
+}
+
+// unicodeString is UNICODE_STRING of the native API.
+type unicodeString struct {
+	Length        uint16
+	MaximumLength uint16
+	Buffer        *uint16
+}
+
+// cmdline returns the command line of pid, which is available on Windows 8.1 or later.
+func cmdline(pid PID) []string {
+	h, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return nil
+	}
+	defer syscall.CloseHandle(h)
+	// The information is a UNICODE_STRING followed by the characters it points to.
+	buf := make([]byte, 64*1024)
+	var size uint32
+	status, _, _ := procNtQueryInformationProcess.Call(uintptr(h), processCommandLineInformation, uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)), uintptr(unsafe.Pointer(&size)))
+	if status != 0 {
+		return nil
+	}
+	us := (*unicodeString)(unsafe.Pointer(&buf[0]))
+	if us.Buffer == nil || us.Length == 0 {
+		return nil
+	}
+	chars := (*[1 << 20]uint16)(unsafe.Pointer(us.Buffer))[: us.Length/2 : us.Length/2]
+	line := string(utf16.Decode(chars))
+	var argc int32
+	argv, err := syscall.CommandLineToArgv(syscall.StringToUTF16Ptr(line), &argc)
+	if err != nil {
+		return nil
+	}
+	defer syscall.LocalFree(syscall.Handle(uintptr(unsafe.Pointer(argv))))
+	args := make([]string, argc)
+	for i := range args {
+		args[i] = syscall.UTF16ToString((*argv[i])[:])
+	}
+	return args
+}
+
 func pathFromPID(pid PID) string {
 	ph := openProcess(pid)
 	defer win.CloseHandle(ph)
@@ -0,0 +1,55 @@
+package app
+
+/*
+#cgo LDFLAGS: -framework CoreGraphics -framework CoreFoundation
+#include <CoreGraphics/CoreGraphics.h>
+
+// capture_window draws the window id alone into out, which is w x h pixels.
+static int capture_window(CGWindowID id, int w, int h, void *out, int stride) {
+	CGImageRef img = CGWindowListCreateImage(CGRectNull, kCGWindowListOptionIncludingWindow, id, kCGWindowImageBoundsIgnoreFraming);
+	if (img == NULL) {
+		return 0;
+	}
+	CGColorSpaceRef space = CGColorSpaceCreateWithName(kCGColorSpaceSRGB);
+	CGContextRef ctx = CGBitmapContextCreate(out, (size_t)w, (size_t)h, 8, stride, space, kCGImageAlphaPremultipliedLast | kCGBitmapByteOrder32Big);
+	CGColorSpaceRelease(space);
+	if (ctx == NULL) {
+		CGImageRelease(img);
+		return 0;
+	}
+	// Retina displays return more pixels than points; drawing scales them to the window size.
+	CGContextDrawImage(ctx, CGRectMake(0, 0, w, h), img);
+	CGContextRelease(ctx);
+	CGImageRelease(img);
+	return 1;
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"image"
+	"unsafe"
+)
+
+func (w *Window) capture() (*image.RGBA, error) {
+	if w.axWindow.IsMinimized() {
+		return nil, errors.New("app: window is not shown")
+	}
+	bounds, err := w.bounds()
+	if err != nil {
+		return nil, err
+	}
+	id, err := w.axWindow.WindowID()
+	if err != nil {
+		return nil, err
+	}
+	img := image.NewRGBA(bounds)
+	if bounds.Empty() {
+		return img, nil
+	}
+	if C.capture_window(C.CGWindowID(id), C.int(bounds.Dx()), C.int(bounds.Dy()), unsafe.Pointer(&img.Pix[0]), C.int(img.Stride)) == 0 {
+		return nil, errors.New("CGWindowListCreateImage failed")
+	}
+	return img, nil
+}
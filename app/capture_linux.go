@@ -0,0 +1,182 @@
+package app
+
+/*
+#cgo LDFLAGS: -lX11 -lXcomposite -lXdamage
+#include <poll.h>
+#include <time.h>
+#include <X11/Xlib.h>
+#include <X11/Xutil.h>
+#include <X11/extensions/Xcomposite.h>
+#include <X11/extensions/Xdamage.h>
+
+static int has_composite(Display *dpy) {
+	int event, error, major = 0, minor = 2;
+	if (!XCompositeQueryExtension(dpy, &event, &error) || !XCompositeQueryVersion(dpy, &major, &minor)) {
+		return 0;
+	}
+	// NameWindowPixmap was added in 0.2.
+	return major > 0 || minor >= 2;
+}
+
+// top_level returns the ancestor of w which is a child of the root window, which is the frame of a reparenting window manager.
+static Window top_level(Display *dpy, Window w) {
+	for (;;) {
+		Window root, parent, *children = NULL;
+		unsigned int n;
+		if (!XQueryTree(dpy, w, &root, &parent, &children, &n)) {
+			return None;
+		}
+		if (children != NULL) {
+			XFree(children);
+		}
+		if (parent == root || parent == None) {
+			return w;
+		}
+		w = parent;
+	}
+}
+
+// get_pixmap_image reads the area of the off-screen pixmap of the redirected window top.
+static XImage *get_pixmap_image(Display *dpy, Window top, int x, int y, int w, int h) {
+	Pixmap pixmap = XCompositeNameWindowPixmap(dpy, top);
+	XImage *img = XGetImage(dpy, pixmap, x, y, w, h, AllPlanes, ZPixmap);
+	XFreePixmap(dpy, pixmap);
+	return img;
+}
+
+static void destroy_image(XImage *img) {
+	XDestroyImage(img);
+}
+
+// paint_watch receives damage and exposure of a window on a connection of its own.
+typedef struct {
+	Display *dpy;
+	Damage damage;
+	int damage_event;
+} paint_watch;
+
+static void watch_paint(Display *dpy, Window w, paint_watch *out) {
+	int error;
+	out->dpy = dpy;
+	out->damage = None;
+	if (XDamageQueryExtension(dpy, &out->damage_event, &error)) {
+		out->damage = XDamageCreate(dpy, w, XDamageReportNonEmpty);
+	}
+	XSelectInput(dpy, w, ExposureMask);
+	XSync(dpy, False);
+}
+
+static void unwatch_paint(paint_watch *p) {
+	if (p->damage != None) {
+		XDamageDestroy(p->dpy, p->damage);
+	}
+	XCloseDisplay(p->dpy);
+}
+
+static long elapsed_ms(struct timespec *start) {
+	struct timespec now;
+	clock_gettime(CLOCK_MONOTONIC, &now);
+	return (now.tv_sec - start->tv_sec) * 1000 + (now.tv_nsec - start->tv_nsec) / 1000000;
+}
+
+// wait_paint waits up to timeout_ms for the window to be damaged or exposed. It returns 1 when it was.
+static int wait_paint(paint_watch *p, int timeout_ms) {
+	struct timespec start;
+	clock_gettime(CLOCK_MONOTONIC, &start);
+	for (;;) {
+		while (XPending(p->dpy)) {
+			XEvent ev;
+			XNextEvent(p->dpy, &ev);
+			if (ev.type == Expose || (p->damage != None && ev.type == p->damage_event + XDamageNotify)) {
+				return 1;
+			}
+		}
+		long remaining = timeout_ms - elapsed_ms(&start);
+		if (remaining <= 0) {
+			return 0;
+		}
+		struct pollfd pfd = { ConnectionNumber(p->dpy), POLLIN, 0 };
+		if (poll(&pfd, 1, remaining) <= 0) {
+			return 0;
+		}
+	}
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"github.com/kbinani/robot/internal/x11"
+	"image"
+	"strconv"
+	"time"
+	"unsafe"
+)
+
+// paintTimeout is how long capture waits at most for a window to repaint into the pixmap allocated by redirecting it.
+const paintTimeout = 500 * time.Millisecond
+
+func (w *Window) capture() (*image.RGBA, error) {
+	dpy, err := openDisplay()
+	if err != nil {
+		return nil, err
+	}
+	xMutex.Lock()
+	defer xMutex.Unlock()
+	if C.has_composite(dpy) == 0 {
+		return nil, ErrNotSupported
+	}
+	bounds, err := w.frameBounds(dpy)
+	if err != nil {
+		return nil, err
+	}
+	top := C.top_level(dpy, w.id)
+	var attr C.XWindowAttributes
+	if top == C.None || C.XGetWindowAttributes(dpy, top, &attr) == 0 {
+		takeXError()
+		return nil, errors.New("app: window does not exist")
+	}
+	if attr.map_state != C.IsViewable {
+		return nil, errors.New("app: window is not shown")
+	}
+
+	// The pixmap covers the top-level window including its border, whose outer corner is at (x, y).
+	origin := image.Pt(int(attr.x), int(attr.y))
+	pixmap := image.Rect(0, 0, int(attr.width+2*attr.border_width), int(attr.height+2*attr.border_width)).Add(origin)
+	area := bounds.Intersect(pixmap)
+	img := image.NewRGBA(bounds)
+	if area.Empty() {
+		return img, nil
+	}
+
+	// Redirection keeps the window contents off-screen. A compositing manager has already
+	// redirected every window; otherwise the window has to paint into the new pixmap first,
+	// which is reported as damage or exposure on a connection of our own.
+	var watch *C.paint_watch
+	if C.XGetSelectionOwner(dpy, atom(dpy, "_NET_WM_CM_S"+strconv.Itoa(int(C.XDefaultScreen(dpy))))) == C.None {
+		if private, err := x11.OpenPrivate(); err == nil {
+			watch = new(C.paint_watch)
+			C.watch_paint((*C.Display)(private), top, watch)
+			defer C.unwatch_paint(watch)
+		}
+	}
+	clearXErrors(dpy)
+	C.XCompositeRedirectWindow(dpy, top, C.CompositeRedirectAutomatic)
+	defer C.XCompositeUnredirectWindow(dpy, top, C.CompositeRedirectAutomatic)
+	C.XSync(dpy, C.False)
+	if watch != nil {
+		C.wait_paint(watch, C.int(paintTimeout/time.Millisecond))
+	}
+
+	src := area.Sub(origin)
+	ximage := C.get_pixmap_image(dpy, top, C.int(src.Min.X), C.int(src.Min.Y), C.int(area.Dx()), C.int(area.Dy()))
+	if ximage == nil || takeXError() != 0 {
+		if ximage != nil {
+			C.destroy_image(ximage)
+		}
+		return nil, errors.New("app: cannot read window pixmap")
+	}
+	defer C.destroy_image(ximage)
+	x11.ConvertImage(unsafe.Pointer(ximage), img, area.Min)
+	return img, nil
+}
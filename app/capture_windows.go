@@ -0,0 +1,58 @@
+package app
+
+import (
+	"errors"
+	"github.com/kbinani/win"
+	"image"
+	"unsafe"
+)
+
+// capture renders the window into a bitmap with PrintWindow, which works while the window is covered.
+func (w *Window) capture() (*image.RGBA, error) {
+	if w.isMinimized() {
+		return nil, errors.New("app: window is not shown")
+	}
+	bounds, err := w.bounds()
+	if err != nil {
+		return nil, err
+	}
+	screen := win.GetDC(0)
+	if screen == 0 {
+		return nil, errors.New("GetDC failed")
+	}
+	defer win.ReleaseDC(0, screen)
+	mem := win.CreateCompatibleDC(screen)
+	if mem == 0 {
+		return nil, errors.New("CreateCompatibleDC failed")
+	}
+	defer win.DeleteDC(mem)
+	width, height := int32(bounds.Dx()), int32(bounds.Dy())
+	bitmap := win.CreateCompatibleBitmap(screen, width, height)
+	if bitmap == 0 {
+		return nil, errors.New("CreateCompatibleBitmap failed")
+	}
+	defer win.DeleteObject(win.HGDIOBJ(bitmap))
+	old := win.SelectObject(mem, win.HGDIOBJ(bitmap))
+	ret, _, _ := procPrintWindow.Call(uintptr(w.hWnd), uintptr(mem), pwRenderFullContent)
+	win.SelectObject(mem, old)
+	if ret == 0 {
+		return nil, errors.New("PrintWindow failed")
+	}
+
+	var info win.BITMAPINFO
+	info.BmiHeader.BiSize = uint32(unsafe.Sizeof(info.BmiHeader))
+	info.BmiHeader.BiWidth = width
+	info.BmiHeader.BiHeight = -height // top-down
+	info.BmiHeader.BiPlanes = 1
+	info.BmiHeader.BiBitCount = 32
+	info.BmiHeader.BiCompression = win.BI_RGB
+	img := image.NewRGBA(bounds)
+	if win.GetDIBits(mem, bitmap, 0, uint32(height), &img.Pix[0], &info, win.DIB_RGB_COLORS) == 0 {
+		return nil, errors.New("GetDIBits failed")
+	}
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+2] = img.Pix[i+2], img.Pix[i]
+		img.Pix[i+3] = 0xff
+	}
+	return img, nil
+}
@@ -0,0 +1,37 @@
+package app
+
+// Desktop is a virtual desktop, also called workspace.
+type Desktop struct {
+	// Index is the number of the desktop, starting at 0.
+	Index int
+	// Name is the name of the desktop, which may be empty.
+	Name string
+}
+
+// AllDesktops is returned by Window.Desktop for windows shown on every desktop.
+const AllDesktops = -1
+
+// Desktops returns the virtual desktops. Only X11 is supported.
+func Desktops() ([]Desktop, error) {
+	return desktops()
+}
+
+// CurrentDesktop returns the index of the desktop shown.
+func CurrentDesktop() (int, error) {
+	return currentDesktop()
+}
+
+// SwitchDesktop asks the window manager to show the n-th desktop.
+func SwitchDesktop(n int) error {
+	return switchDesktop(n)
+}
+
+// Desktop returns the index of the desktop the window is on, or AllDesktops.
+func (w *Window) Desktop() (int, error) {
+	return w.desktop()
+}
+
+// MoveToDesktop asks the window manager to move the window to the n-th desktop, or to every desktop when n is AllDesktops.
+func (w *Window) MoveToDesktop(n int) error {
+	return w.moveToDesktop(n)
+}
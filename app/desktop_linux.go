@@ -0,0 +1,125 @@
+package app
+
+// #include <X11/Xlib.h>
+import "C"
+
+import (
+	"fmt"
+	"strings"
+)
+
+// allDesktopsValue is the value of _NET_WM_DESKTOP for windows on every desktop.
+const allDesktopsValue = 0xffffffff
+
+func desktops() ([]Desktop, error) {
+	dpy, err := openDisplay()
+	if err != nil {
+		return nil, err
+	}
+	xMutex.Lock()
+	defer xMutex.Unlock()
+	root := rootWindow(dpy)
+	count, ok := cardinalProperty(dpy, root, "_NET_NUMBER_OF_DESKTOPS")
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	names, _ := stringProperty(dpy, root, "_NET_DESKTOP_NAMES")
+	return desktopList(int(count), names), nil
+}
+
+// desktopList returns count desktops named by names, the value of
+// _NET_DESKTOP_NAMES. Names are terminated by NUL, and may be fewer than the
+// desktops.
+func desktopList(count int, names string) []Desktop {
+	split := strings.Split(names, "\x00")
+	result := make([]Desktop, count)
+	for i := range result {
+		result[i].Index = i
+		if i < len(split) {
+			result[i].Name = split[i]
+		}
+	}
+	return result
+}
+
+func currentDesktop() (int, error) {
+	dpy, err := openDisplay()
+	if err != nil {
+		return 0, err
+	}
+	xMutex.Lock()
+	defer xMutex.Unlock()
+	n, ok := cardinalProperty(dpy, rootWindow(dpy), "_NET_CURRENT_DESKTOP")
+	if !ok {
+		return 0, ErrNotSupported
+	}
+	return int(n), nil
+}
+
+// checkDesktop returns an error unless n is the index of a desktop, or AllDesktops when all is true. Callers must hold xMutex.
+func checkDesktop(dpy *C.Display, n int, all bool) error {
+	count, ok := cardinalProperty(dpy, rootWindow(dpy), "_NET_NUMBER_OF_DESKTOPS")
+	if !ok {
+		return ErrNotSupported
+	}
+	return validDesktop(n, int(count), all)
+}
+
+// validDesktop returns an error unless n is the index of one of count desktops, or AllDesktops when all is true.
+func validDesktop(n, count int, all bool) error {
+	if (n < 0 || n >= count) && !(all && n == AllDesktops) {
+		return fmt.Errorf("app: desktop %d does not exist", n)
+	}
+	return nil
+}
+
+func switchDesktop(n int) error {
+	dpy, err := openDisplay()
+	if err != nil {
+		return err
+	}
+	xMutex.Lock()
+	defer xMutex.Unlock()
+	if err := checkDesktop(dpy, n, false); err != nil {
+		return err
+	}
+	sendClientMessage(dpy, rootWindow(dpy), "_NET_CURRENT_DESKTOP", int64(n), C.CurrentTime)
+	return nil
+}
+
+func (w *Window) desktop() (int, error) {
+	dpy, err := openDisplay()
+	if err != nil {
+		return 0, err
+	}
+	xMutex.Lock()
+	defer xMutex.Unlock()
+	n, ok := cardinalProperty(dpy, w.id, "_NET_WM_DESKTOP")
+	if !ok {
+		return 0, ErrNotSupported
+	}
+	// Xlib may sign-extend items of format 32, so only the low 32 bits are compared.
+	if uint32(n) == allDesktopsValue {
+		return AllDesktops, nil
+	}
+	return int(n), nil
+}
+
+func (w *Window) moveToDesktop(n int) error {
+	dpy, err := openDisplay()
+	if err != nil {
+		return err
+	}
+	xMutex.Lock()
+	defer xMutex.Unlock()
+	if err := checkDesktop(dpy, n, true); err != nil {
+		return err
+	}
+	value := int64(n)
+	if n == AllDesktops {
+		value = allDesktopsValue
+	}
+	// Source indication 2 marks a pager, as in activate.
+	sendClientMessage(dpy, w.id, "_NET_WM_DESKTOP", value, 2)
+	return nil
+}
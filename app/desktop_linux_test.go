@@ -0,0 +1,120 @@
+package app
+
+import (
+	"github.com/kbinani/robot/internal/xvfb"
+	"reflect"
+	"testing"
+)
+
+func TestDesktopList(t *testing.T) {
+	tests := []struct {
+		count int
+		names string
+		want  []Desktop
+	}{
+		{0, "", []Desktop{}},
+		{2, "Main\x00Web\x00", []Desktop{{0, "Main"}, {1, "Web"}}},
+		// Names may be fewer than desktops, or more.
+		{3, "Main\x00", []Desktop{{0, "Main"}, {1, ""}, {2, ""}}},
+		{1, "Main\x00Web\x00", []Desktop{{0, "Main"}}},
+		{2, "", []Desktop{{0, ""}, {1, ""}}},
+		// The last name may lack its terminator.
+		{2, "Main\x00Web", []Desktop{{0, "Main"}, {1, "Web"}}},
+	}
+	for _, tt := range tests {
+		if got := desktopList(tt.count, tt.names); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("desktopList(%d, %q) = %v, want %v", tt.count, tt.names, got, tt.want)
+		}
+	}
+}
+
+func TestValidDesktop(t *testing.T) {
+	tests := []struct {
+		n     int
+		count int
+		all   bool
+		valid bool
+	}{
+		{0, 2, false, true},
+		{1, 2, false, true},
+		{2, 2, false, false},
+		{-2, 2, true, false},
+		{AllDesktops, 2, false, false},
+		{AllDesktops, 2, true, true},
+		{0, 0, false, false},
+	}
+	for _, tt := range tests {
+		if err := validDesktop(tt.n, tt.count, tt.all); (err == nil) != tt.valid {
+			t.Errorf("validDesktop(%d, %d, %v) = %v, want valid %v", tt.n, tt.count, tt.all, err, tt.valid)
+		}
+	}
+}
+
+func TestDesktopsWithoutSupport(t *testing.T) {
+	needX(t)
+	startWM(t, xvfb.EWMH...)
+	if _, err := Desktops(); err != ErrNotSupported {
+		t.Errorf("Desktops returned %v, want ErrNotSupported", err)
+	}
+	if _, err := CurrentDesktop(); err != ErrNotSupported {
+		t.Errorf("CurrentDesktop returned %v, want ErrNotSupported", err)
+	}
+	if err := SwitchDesktop(0); err != ErrNotSupported {
+		t.Errorf("SwitchDesktop returned %v, want ErrNotSupported", err)
+	}
+}
+
+func TestDesktops(t *testing.T) {
+	needX(t)
+	wm := startWM(t, xvfb.EWMH...)
+	wm.SetDesktops(3, "Main", "Web")
+
+	desktops, err := Desktops()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Desktop{{0, "Main"}, {1, "Web"}, {2, ""}}; !reflect.DeepEqual(desktops, want) {
+		t.Errorf("Desktops() = %v, want %v", desktops, want)
+	}
+	if n, err := CurrentDesktop(); n != 0 || err != nil {
+		t.Errorf("CurrentDesktop() = %d, %v, want 0", n, err)
+	}
+	if err := SwitchDesktop(2); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "desktop 2", func() bool {
+		n, _ := CurrentDesktop()
+		return n == 2
+	})
+	if err := SwitchDesktop(3); err == nil {
+		t.Error("switched to desktop 3 of 3")
+	}
+}
+
+func TestMoveToDesktop(t *testing.T) {
+	needX(t)
+	wm := startWM(t, xvfb.EWMH...)
+	wm.SetDesktops(2)
+	_, w := newTestWindow(t, xvfb.WindowOptions{Title: "desktop"})
+
+	if n, err := w.Desktop(); n != 0 || err != nil {
+		t.Errorf("Desktop() = %d, %v, want 0", n, err)
+	}
+	if err := w.MoveToDesktop(1); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "desktop 1", func() bool {
+		n, _ := w.Desktop()
+		return n == 1
+	})
+	if err := w.MoveToDesktop(AllDesktops); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "all desktops", func() bool {
+		n, _ := w.Desktop()
+		return n == AllDesktops
+	})
+	if err := w.MoveToDesktop(2); err == nil {
+		t.Error("moved to desktop 2 of 2")
+	}
+}
@@ -0,0 +1,21 @@
+package app
+
+func desktops() ([]Desktop, error) {
+	return nil, ErrNotSupported
+}
+
+func currentDesktop() (int, error) {
+	return 0, ErrNotSupported
+}
+
+func switchDesktop(n int) error {
+	return ErrNotSupported
+}
+
+func (w *Window) desktop() (int, error) {
+	return 0, ErrNotSupported
+}
+
+func (w *Window) moveToDesktop(n int) error {
+	return ErrNotSupported
+}
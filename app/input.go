@@ -0,0 +1,43 @@
+package app
+
+import (
+	"github.com/kbinani/robot/key"
+	"image"
+)
+
+// The methods below send input to a window directly, instead of injecting
+// it into the input system like robot.Kbd and robot.Btn. The window does
+// not need to be focused or visible on top, so other windows can be used
+// meanwhile. Not every app accepts such input:
+//
+// On X11 the events are sent with XSendEvent, and carry a flag telling that
+// they are synthetic. Clients built on Xlib, Xt, Motif and Tk accept them.
+// xterm ignores them unless its allowSendEvents resource is set. GTK 3 and
+// Qt 5 accept key events, but read the pointer through XInput 2, so Click
+// may not reach them.
+//
+// On Windows the messages are posted to the window. They do not change the
+// keyboard state returned by GetKeyState, so shortcuts with modifiers are
+// usually not recognized, and apps drawing their own controls, such as
+// browsers, may ignore Click.
+//
+// On macOS the events are posted to the process of the window, which
+// delivers them to its key window, so the target window should be the key
+// window of its app. Many apps ignore Click while they are not active.
+
+// SendKeys presses codes in order and releases them in reverse order, e.g.
+// SendKeys(key.Control, key.S) for a shortcut.
+func (w *Window) SendKeys(codes ...key.Code) error {
+	return w.sendKeys(codes)
+}
+
+// Type types text into the window. Which characters can be typed depends
+// on the platform: on X11, those on the current keyboard mapping.
+func (w *Window) Type(text string) error {
+	return w.typeText(text)
+}
+
+// Click clicks the left button at p, relative to the top-left corner of the Bounds of the window.
+func (w *Window) Click(p image.Point) error {
+	return w.click(p)
+}
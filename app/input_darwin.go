@@ -0,0 +1,122 @@
+package app
+
+/*
+#cgo LDFLAGS: -framework ApplicationServices
+#include <ApplicationServices/ApplicationServices.h>
+
+static void post_key(pid_t pid, CGKeyCode code, bool down, CGEventFlags flags) {
+	CGEventRef ev = CGEventCreateKeyboardEvent(NULL, code, down);
+	if (ev == NULL) {
+		return;
+	}
+	CGEventSetFlags(ev, flags);
+	CGEventPostToPid(pid, ev);
+	CFRelease(ev);
+}
+
+// post_text posts a key press and release which type the UTF-16 characters.
+static void post_text(pid_t pid, UniChar *chars, int length) {
+	for (int i = 0; i < 2; i++) {
+		CGEventRef ev = CGEventCreateKeyboardEvent(NULL, 0, i == 0);
+		if (ev == NULL) {
+			return;
+		}
+		CGEventKeyboardSetUnicodeString(ev, length, chars);
+		CGEventPostToPid(pid, ev);
+		CFRelease(ev);
+	}
+}
+
+static void post_click(pid_t pid, double x, double y) {
+	CGEventType types[] = { kCGEventMouseMoved, kCGEventLeftMouseDown, kCGEventLeftMouseUp };
+	for (int i = 0; i < 3; i++) {
+		CGEventRef ev = CGEventCreateMouseEvent(NULL, types[i], CGPointMake(x, y), kCGMouseButtonLeft);
+		if (ev == NULL) {
+			return;
+		}
+		CGEventPostToPid(pid, ev);
+		CFRelease(ev);
+	}
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"github.com/kbinani/robot"
+	"github.com/kbinani/robot/key"
+	"image"
+	"unicode/utf16"
+)
+
+// modifierFlags maps kVK codes of modifier keys to the flags they set while held.
+var modifierFlags = map[int]C.CGEventFlags{
+	C.kVK_Shift:        C.kCGEventFlagMaskShift,
+	C.kVK_RightShift:   C.kCGEventFlagMaskShift,
+	C.kVK_Control:      C.kCGEventFlagMaskControl,
+	C.kVK_RightControl: C.kCGEventFlagMaskControl,
+	C.kVK_Option:       C.kCGEventFlagMaskAlternate,
+	C.kVK_Command:      C.kCGEventFlagMaskCommand,
+}
+
+func (w *Window) processID() (C.pid_t, error) {
+	pid := w.pid()
+	if pid == 0 {
+		return 0, errors.New("app: window does not exist")
+	}
+	return C.pid_t(pid), nil
+}
+
+func (w *Window) sendKeys(codes []key.Code) error {
+	pid, err := w.processID()
+	if err != nil {
+		return err
+	}
+	native := make([]int, len(codes))
+	for i, code := range codes {
+		n, ok := robot.NativeKeyCode(code)
+		if !ok {
+			return fmt.Errorf("app: key %d is not supported", code)
+		}
+		if n == C.kCGEventFlagMaskCommand {
+			n = C.kVK_Command
+		}
+		native[i] = n
+	}
+	flags := make([]C.CGEventFlags, len(codes)+1)
+	for i, n := range native {
+		flags[i+1] = flags[i] | modifierFlags[n]
+		C.post_key(pid, C.CGKeyCode(n), true, flags[i+1])
+	}
+	for i := len(native) - 1; i >= 0; i-- {
+		C.post_key(pid, C.CGKeyCode(native[i]), false, flags[i])
+	}
+	return nil
+}
+
+func (w *Window) typeText(text string) error {
+	pid, err := w.processID()
+	if err != nil {
+		return err
+	}
+	for _, r := range text {
+		chars := utf16.Encode([]rune{r})
+		C.post_text(pid, (*C.UniChar)(&chars[0]), C.int(len(chars)))
+	}
+	return nil
+}
+
+func (w *Window) click(p image.Point) error {
+	pid, err := w.processID()
+	if err != nil {
+		return err
+	}
+	bounds, err := w.bounds()
+	if err != nil {
+		return err
+	}
+	screen := bounds.Min.Add(p)
+	C.post_click(pid, C.double(screen.X), C.double(screen.Y))
+	return nil
+}
@@ -0,0 +1,221 @@
+package app
+
+/*
+#include <X11/Xlib.h>
+#include <X11/XKBlib.h>
+#include <X11/keysym.h>
+
+static void send_key(Display *dpy, Window w, int press, unsigned int keycode, unsigned int state) {
+	XEvent ev = {0};
+	ev.xkey.type = press ? KeyPress : KeyRelease;
+	ev.xkey.display = dpy;
+	ev.xkey.window = w;
+	ev.xkey.root = DefaultRootWindow(dpy);
+	ev.xkey.subwindow = None;
+	ev.xkey.time = CurrentTime;
+	ev.xkey.x = ev.xkey.y = 1;
+	ev.xkey.x_root = ev.xkey.y_root = 1;
+	ev.xkey.state = state;
+	ev.xkey.keycode = keycode;
+	ev.xkey.same_screen = True;
+	XSendEvent(dpy, w, True, press ? KeyPressMask : KeyReleaseMask, &ev);
+}
+
+// send_pointer sends an event of type to w, at (x, y) in w and (root_x, root_y) on the root window.
+static void send_pointer(Display *dpy, Window w, int type, int x, int y, int root_x, int root_y, unsigned int state) {
+	XEvent ev = {0};
+	long mask = 0;
+	ev.type = type;
+	switch (type) {
+	case EnterNotify:
+		ev.xcrossing.display = dpy;
+		ev.xcrossing.window = w;
+		ev.xcrossing.root = DefaultRootWindow(dpy);
+		ev.xcrossing.time = CurrentTime;
+		ev.xcrossing.x = x;
+		ev.xcrossing.y = y;
+		ev.xcrossing.x_root = root_x;
+		ev.xcrossing.y_root = root_y;
+		ev.xcrossing.mode = NotifyNormal;
+		ev.xcrossing.detail = NotifyAncestor;
+		ev.xcrossing.same_screen = True;
+		ev.xcrossing.focus = False;
+		mask = EnterWindowMask;
+		break;
+	case MotionNotify:
+		ev.xmotion.display = dpy;
+		ev.xmotion.window = w;
+		ev.xmotion.root = DefaultRootWindow(dpy);
+		ev.xmotion.time = CurrentTime;
+		ev.xmotion.x = x;
+		ev.xmotion.y = y;
+		ev.xmotion.x_root = root_x;
+		ev.xmotion.y_root = root_y;
+		ev.xmotion.state = state;
+		ev.xmotion.same_screen = True;
+		mask = PointerMotionMask;
+		break;
+	default:
+		ev.xbutton.display = dpy;
+		ev.xbutton.window = w;
+		ev.xbutton.root = DefaultRootWindow(dpy);
+		ev.xbutton.time = CurrentTime;
+		ev.xbutton.x = x;
+		ev.xbutton.y = y;
+		ev.xbutton.x_root = root_x;
+		ev.xbutton.y_root = root_y;
+		ev.xbutton.state = state;
+		ev.xbutton.button = Button1;
+		ev.xbutton.same_screen = True;
+		mask = type == ButtonPress ? ButtonPressMask : ButtonReleaseMask;
+	}
+	XSendEvent(dpy, w, True, mask, &ev);
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"github.com/kbinani/robot/internal/x11"
+	"github.com/kbinani/robot/key"
+	"image"
+)
+
+// modifierMasks maps keysyms of modifier keys to the state bits they set while held.
+var modifierMasks = map[C.KeySym]C.uint{
+	C.XK_Shift_L:   C.ShiftMask,
+	C.XK_Shift_R:   C.ShiftMask,
+	C.XK_Control_L: C.ControlMask,
+	C.XK_Control_R: C.ControlMask,
+	C.XK_Alt_L:     C.Mod1Mask,
+	C.XK_Super_L:   C.Mod4Mask,
+}
+
+func (w *Window) sendKeys(codes []key.Code) error {
+	dpy, err := openDisplay()
+	if err != nil {
+		return err
+	}
+	xMutex.Lock()
+	defer xMutex.Unlock()
+	keycodes := make([]C.uint, len(codes))
+	for i, code := range codes {
+		sym, ok := x11.Keysym(code)
+		if !ok {
+			return fmt.Errorf("app: key %d is not supported", code)
+		}
+		if keycodes[i] = C.uint(C.XKeysymToKeycode(dpy, C.KeySym(sym))); keycodes[i] == 0 {
+			return fmt.Errorf("app: key %d is not on the keyboard mapping", code)
+		}
+	}
+	clearXErrors(dpy)
+	states := make([]C.uint, len(codes)+1)
+	for i, code := range codes {
+		C.send_key(dpy, w.id, 1, keycodes[i], states[i])
+		sym, _ := x11.Keysym(code)
+		states[i+1] = states[i] | modifierMasks[C.KeySym(sym)]
+	}
+	for i := len(codes) - 1; i >= 0; i-- {
+		C.send_key(dpy, w.id, 0, keycodes[i], states[i+1])
+	}
+	// Wait for the server to process the events, so that an error tells a destroyed window.
+	C.XSync(dpy, C.False)
+	if takeXError() != 0 {
+		return errors.New("app: window does not exist")
+	}
+	return nil
+}
+
+// keysymForRune returns the keysym which types r.
+func keysymForRune(r rune) C.KeySym {
+	switch {
+	case r == '\n':
+		return C.XK_Return
+	case r == '\t':
+		return C.XK_Tab
+	case 0x20 <= r && r < 0x7f, 0xa0 <= r && r <= 0xff:
+		// Keysyms of Latin-1 are equal to the code points.
+		return C.KeySym(r)
+	}
+	return C.KeySym(0x01000000 | r)
+}
+
+func (w *Window) typeText(text string) error {
+	dpy, err := openDisplay()
+	if err != nil {
+		return err
+	}
+	xMutex.Lock()
+	defer xMutex.Unlock()
+	type stroke struct {
+		keycode C.uint
+		state   C.uint
+	}
+	strokes := []stroke{}
+	for _, r := range text {
+		sym := keysymForRune(r)
+		keycode := C.XKeysymToKeycode(dpy, sym)
+		if keycode == 0 {
+			return fmt.Errorf("app: cannot type %q", r)
+		}
+		switch sym {
+		case C.XkbKeycodeToKeysym(dpy, keycode, 0, 0):
+			strokes = append(strokes, stroke{C.uint(keycode), 0})
+		case C.XkbKeycodeToKeysym(dpy, keycode, 0, 1):
+			strokes = append(strokes, stroke{C.uint(keycode), C.ShiftMask})
+		default:
+			// Characters typed with AltGr or other levels are not supported.
+			return fmt.Errorf("app: cannot type %q", r)
+		}
+	}
+	clearXErrors(dpy)
+	for _, s := range strokes {
+		C.send_key(dpy, w.id, 1, s.keycode, s.state)
+		C.send_key(dpy, w.id, 0, s.keycode, s.state)
+	}
+	C.XSync(dpy, C.False)
+	if takeXError() != 0 {
+		return errors.New("app: window does not exist")
+	}
+	return nil
+}
+
+func (w *Window) click(p image.Point) error {
+	dpy, err := openDisplay()
+	if err != nil {
+		return err
+	}
+	xMutex.Lock()
+	defer xMutex.Unlock()
+	bounds, err := w.frameBounds(dpy)
+	if err != nil {
+		return err
+	}
+	left, _, top, _ := w.frameExtents(dpy)
+	root := bounds.Min.Add(p)
+
+	// Deliver the events to the innermost subwindow at the point, as the X server does.
+	target := w.id
+	x, y := C.int(p.X-left), C.int(p.Y-top)
+	for {
+		var cx, cy C.int
+		var child C.Window
+		if C.XTranslateCoordinates(dpy, target, target, x, y, &cx, &cy, &child) == 0 || child == C.None {
+			break
+		}
+		C.XTranslateCoordinates(dpy, target, child, x, y, &cx, &cy, &child)
+		target, x, y = child, cx, cy
+	}
+	rx, ry := C.int(root.X), C.int(root.Y)
+	clearXErrors(dpy)
+	C.send_pointer(dpy, target, C.EnterNotify, x, y, rx, ry, 0)
+	C.send_pointer(dpy, target, C.MotionNotify, x, y, rx, ry, 0)
+	C.send_pointer(dpy, target, C.ButtonPress, x, y, rx, ry, 0)
+	C.send_pointer(dpy, target, C.ButtonRelease, x, y, rx, ry, C.Button1Mask)
+	C.XSync(dpy, C.False)
+	if takeXError() != 0 {
+		return errors.New("app: window does not exist")
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package app
+
+import (
+	"github.com/kbinani/robot"
+	"github.com/kbinani/robot/internal/xvfb"
+	"github.com/kbinani/robot/key"
+	"image"
+	"testing"
+)
+
+const (
+	xkControlL  = 0xffe3
+	controlMask = 1 << 2
+	shiftMask   = 1 << 0
+	button1Mask = 1 << 8
+)
+
+// receivedEvents waits until client has received n events of the given types, and returns them.
+func receivedEvents(t *testing.T, client *xvfb.Window, n int, types ...int) []xvfb.Event {
+	t.Helper()
+	var events []xvfb.Event
+	eventually(t, "events", func() bool {
+		events = []xvfb.Event{}
+		for _, e := range client.Events() {
+			for _, typ := range types {
+				if e.Type == typ {
+					events = append(events, e)
+				}
+			}
+		}
+		return len(events) >= n
+	})
+	for _, e := range events {
+		if !e.SendEvent {
+			t.Errorf("event %+v is not marked as sent", e)
+		}
+	}
+	return events
+}
+
+func TestSendKeysDelivered(t *testing.T) {
+	needX(t)
+	client, w := newTestWindow(t, xvfb.WindowOptions{Title: "keys"})
+	if err := w.SendKeys(key.Control, key.S); err != nil {
+		t.Fatal(err)
+	}
+	events := receivedEvents(t, client, 4, xvfb.KeyPress, xvfb.KeyRelease)
+	want := []struct {
+		typ    int
+		keysym uint64
+		state  uint
+	}{
+		{xvfb.KeyPress, xkControlL, 0},
+		{xvfb.KeyPress, 's', controlMask},
+		{xvfb.KeyRelease, 's', controlMask},
+		{xvfb.KeyRelease, xkControlL, controlMask},
+	}
+	for i, e := range events[:len(want)] {
+		if e.Type != want[i].typ || e.Keysym != want[i].keysym || e.State != want[i].state {
+			t.Errorf("event %d is %+v, want type %d keysym %#x state %#x", i, e, want[i].typ, want[i].keysym, want[i].state)
+		}
+	}
+}
+
+func TestTypeDelivered(t *testing.T) {
+	needX(t)
+	client, w := newTestWindow(t, xvfb.WindowOptions{Title: "type"})
+	text := "aB1!"
+	if err := w.Type(text); err != nil {
+		t.Fatal(err)
+	}
+	events := receivedEvents(t, client, len(text), xvfb.KeyPress)
+	for i, r := range text {
+		e := events[i]
+		if e.Keysym != uint64(r) {
+			t.Errorf("key %d is %#x, want %q", i, e.Keysym, r)
+		}
+		shifted := r == 'B' || r == '!'
+		if (e.State&shiftMask != 0) != shifted {
+			t.Errorf("key %d %q has state %#x", i, r, e.State)
+		}
+	}
+	if err := w.Type("€あ"); err == nil {
+		t.Error("typing characters missing from the keyboard mapping did not fail")
+	}
+}
+
+func TestClickDelivered(t *testing.T) {
+	needX(t)
+	client, w := newTestWindow(t, xvfb.WindowOptions{Title: "click", Bounds: image.Rect(100, 100, 300, 250)})
+	if err := w.Click(image.Pt(10, 20)); err != nil {
+		t.Fatal(err)
+	}
+	events := receivedEvents(t, client, 2, xvfb.ButtonPress, xvfb.ButtonRelease)
+	press, release := events[0], events[1]
+	if press.Type != xvfb.ButtonPress || press.Button != 1 || press.Pos != image.Pt(10, 20) || press.State != 0 {
+		t.Errorf("press is %+v", press)
+	}
+	if release.Type != xvfb.ButtonRelease || release.Button != 1 || release.Pos != image.Pt(10, 20) || release.State != button1Mask {
+		t.Errorf("release is %+v", release)
+	}
+}
+
+func TestInputToDestroyedWindow(t *testing.T) {
+	needX(t)
+	client, w := newTestWindow(t, xvfb.WindowOptions{Title: "gone"})
+	client.Close()
+	if err := w.SendKeys(key.A); err == nil {
+		t.Error("SendKeys to a destroyed window did not fail")
+	}
+}
+
+func TestErrorOfDestroyedWindowIsDropped(t *testing.T) {
+	needX(t)
+	closed, gone := newTestWindow(t, xvfb.WindowOptions{Title: "gone"})
+	client, w := newTestWindow(t, xvfb.WindowOptions{Title: "alive", Bounds: image.Rect(400, 100, 600, 250)})
+	closed.Close()
+	eventually(t, "window to be destroyed", func() bool {
+		_, err := gone.Bounds()
+		return err != nil
+	})
+	// Reading a property of the destroyed window fails with BadWindow, which must not fail later requests.
+	if title := gone.Title(); title != "" {
+		t.Errorf("Title of destroyed window is %q", title)
+	}
+	if err := robot.Mmv(image.Pt(10, 20)); err != nil {
+		t.Errorf("Mmv failed after BadWindow: %v", err)
+	}
+	gone.Title()
+	if _, err := robot.Capture(image.Rect(0, 0, 10, 10)); err != nil {
+		t.Errorf("Capture failed after BadWindow: %v", err)
+	}
+	gone.Title()
+	if err := w.SendKeys(key.A); err != nil {
+		t.Errorf("SendKeys failed after BadWindow: %v", err)
+	}
+	receivedEvents(t, client, 2, xvfb.KeyPress, xvfb.KeyRelease)
+}
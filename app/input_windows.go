@@ -0,0 +1,104 @@
+package app
+
+import (
+	"github.com/kbinani/robot/key"
+	"github.com/kbinani/win"
+	"image"
+	"unicode/utf16"
+	"unsafe"
+)
+
+// focus returns the control of the window which receives keyboard input, which is the window itself when the control is unknown.
+func (w *Window) focus() uintptr {
+	tid := win.GetWindowThreadProcessId(w.hWnd, nil)
+	var info guiThreadInfo
+	info.cbSize = uint32(unsafe.Sizeof(info))
+	if ret, _, _ := procGetGUIThreadInfo.Call(uintptr(tid), uintptr(unsafe.Pointer(&info))); ret == 0 || info.hwndFocus == 0 {
+		return uintptr(w.hWnd)
+	}
+	return info.hwndFocus
+}
+
+// keyLParam returns lParam of WM_KEYDOWN and WM_KEYUP, which holds the repeat count, scan code and transition state.
+func keyLParam(code key.Code, up bool) uintptr {
+	scan, _, _ := procMapVirtualKey.Call(uintptr(code), mapvkVKToVSC)
+	l := uint32(1) | uint32(scan&0xff)<<16
+	if up {
+		l |= 1<<30 | 1<<31
+	}
+	return uintptr(l)
+}
+
+func (w *Window) sendKeys(codes []key.Code) error {
+	target := win.HWND(w.focus())
+	alt := false
+	for _, code := range codes {
+		msg := uint32(wmKeyDown)
+		if alt || code == key.Menu {
+			// Keys pressed with Alt are system keys.
+			msg = wmSysKeyDown
+		}
+		win.PostMessage(target, msg, uintptr(code), keyLParam(code, false))
+		alt = alt || code == key.Menu
+	}
+	for i := len(codes) - 1; i >= 0; i-- {
+		msg := uint32(wmKeyUp)
+		if alt {
+			msg = wmSysKeyUp
+		}
+		win.PostMessage(target, msg, uintptr(codes[i]), keyLParam(codes[i], true))
+		if codes[i] == key.Menu {
+			alt = false
+		}
+	}
+	return nil
+}
+
+func (w *Window) typeText(text string) error {
+	target := win.HWND(w.focus())
+	for _, c := range utf16.Encode([]rune(text)) {
+		if c == '\n' {
+			c = '\r'
+		}
+		win.PostMessage(target, wmChar, uintptr(c), 1)
+	}
+	return nil
+}
+
+// childWindowFromPoint is ChildWindowFromPointEx, which takes a POINT by value.
+func childWindowFromPoint(parent uintptr, pt point) uintptr {
+	const flags = cwpSkipInvisible | cwpSkipTransparent
+	var ret uintptr
+	if unsafe.Sizeof(uintptr(0)) == 8 {
+		ret, _, _ = procChildWindowFromPointEx.Call(parent, *(*uintptr)(unsafe.Pointer(&pt)), flags)
+	} else {
+		ret, _, _ = procChildWindowFromPointEx.Call(parent, uintptr(pt.X), uintptr(pt.Y), flags)
+	}
+	return ret
+}
+
+func (w *Window) click(p image.Point) error {
+	bounds, err := w.bounds()
+	if err != nil {
+		return err
+	}
+	screen := bounds.Min.Add(p)
+
+	// Controls are child windows, so post the messages to the innermost one at the point.
+	target := uintptr(w.hWnd)
+	var pt point
+	for {
+		pt = point{int32(screen.X), int32(screen.Y)}
+		procScreenToClient.Call(target, uintptr(unsafe.Pointer(&pt)))
+		child := childWindowFromPoint(target, pt)
+		if child == 0 || child == target {
+			break
+		}
+		target = child
+	}
+	lParam := uintptr(uint32(uint16(pt.X)) | uint32(uint16(pt.Y))<<16)
+	win.PostMessage(win.HWND(target), wmMouseMove, 0, lParam)
+	win.PostMessage(win.HWND(target), wmLButtonDown, mkLButton, lParam)
+	win.PostMessage(win.HWND(target), wmLButtonUp, 0, lParam)
+	return nil
+}
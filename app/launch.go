@@ -0,0 +1,172 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LaunchOptions configures Launch.
+type LaunchOptions struct {
+	// Env lists environment variables in the form "key=value", added to the environment of this process.
+	Env []string
+	// Dir is the working directory. Default is the current directory.
+	Dir string
+	// Display is the X display the app connects to, on X11. Default is $DISPLAY.
+	// Windows of this package are always looked up on $DISPLAY.
+	Display string
+}
+
+// maxOutput is the number of bytes of stdout and stderr each retained for a launched app. Older output is dropped.
+const maxOutput = 1 << 20
+
+// outputDelay is how long output is read after a launched app exits, while other processes keep its stdout or stderr open.
+const outputDelay = 100 * time.Millisecond
+
+// tailBuffer retains the last maxOutput bytes written to it.
+type tailBuffer struct {
+	mutex sync.Mutex
+	buf   []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.buf = append(b.buf, p...)
+	if over := len(b.buf) - maxOutput; over > 0 {
+		b.buf = append(b.buf[:0], b.buf[over:]...)
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return string(b.buf)
+}
+
+// process is a child process started by Launch.
+type process struct {
+	cmd    *exec.Cmd
+	stdout tailBuffer
+	stderr tailBuffer
+	// done is closed when the process has exited, and err is set to the result of cmd.Wait before.
+	done chan struct{}
+	err  error
+}
+
+var (
+	runningMutex sync.Mutex
+	// running holds processes started by Launch until they are reaped, so
+	// that apps found by their PID meanwhile share the output and status.
+	// Afterwards the PID may be reused, and only the App returned by Launch
+	// keeps the process.
+	running = map[PID]*process{}
+)
+
+// launchedProcess returns the process of app if it was started by Launch, or nil.
+func (app *App) launchedProcess() *process {
+	if app.proc != nil {
+		return app.proc
+	}
+	runningMutex.Lock()
+	defer runningMutex.Unlock()
+	return running[app.pid]
+}
+
+// Launch starts the executable at path with args, and returns the app. The
+// stdout and stderr of the app are retained, see App.Output.
+func Launch(path string, args []string, opts LaunchOptions) (*App, error) {
+	p := &process{cmd: exec.Command(path, args...), done: make(chan struct{})}
+	p.cmd.Dir = opts.Dir
+	p.cmd.Env = append(os.Environ(), opts.Env...)
+	if opts.Display != "" {
+		p.cmd.Env = append(p.cmd.Env, "DISPLAY="+opts.Display)
+	}
+	p.cmd.Stdout = &p.stdout
+	p.cmd.Stderr = &p.stderr
+	// Children of the app, such as daemons spawned by launcher scripts, may
+	// keep stdout and stderr open after it exits. Output written by them later is dropped.
+	p.cmd.WaitDelay = outputDelay
+	if err := p.cmd.Start(); err != nil {
+		return nil, err
+	}
+	pid := PID(p.cmd.Process.Pid)
+	runningMutex.Lock()
+	running[pid] = p
+	runningMutex.Unlock()
+	go func() {
+		p.err = p.cmd.Wait()
+		if errors.Is(p.err, exec.ErrWaitDelay) {
+			// The app itself exited normally.
+			p.err = nil
+		}
+		runningMutex.Lock()
+		delete(running, pid)
+		runningMutex.Unlock()
+		close(p.done)
+	}()
+	app := newApp(pid)
+	app.proc = p
+	return app, nil
+}
+
+// Output returns the stdout and stderr of an app started by Launch, up to
+// the last megabyte of each. It returns empty strings for other apps.
+func (app *App) Output() (stdout, stderr string) {
+	p := app.launchedProcess()
+	if p == nil {
+		return "", ""
+	}
+	return p.stdout.String(), p.stderr.String()
+}
+
+// WaitForWindow blocks until the app shows a window, and returns it. For an
+// app started by Launch, it fails as soon as the app exits.
+func (app *App) WaitForWindow(ctx context.Context) (*Window, error) {
+	visible := func() []*Window {
+		ret := []*Window{}
+		for _, w := range app.windows() {
+			if w.isVisible() {
+				ret = append(ret, w)
+			}
+		}
+		return ret
+	}
+	p := app.launchedProcess()
+	if p == nil {
+		return waitForWindow(ctx, Match{}, visible)
+	}
+	waitCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-p.done:
+			cancel()
+		case <-waitCtx.Done():
+		}
+	}()
+	w, err := waitForWindow(waitCtx, Match{}, visible)
+	select {
+	case <-p.done:
+		if err == nil {
+			return w, nil
+		}
+		status := "exit status 0"
+		if p.err != nil {
+			status = p.err.Error()
+		}
+		msg := fmt.Sprintf("app: %s exited before showing a window: %s", p.cmd.Path, status)
+		if stderr := strings.TrimSpace(p.stderr.String()); stderr != "" {
+			msg += "\n" + stderr
+		}
+		return nil, errors.New(msg)
+	default:
+	}
+	return w, err
+}
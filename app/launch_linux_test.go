@@ -0,0 +1,79 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLaunchOutputAndExitCode(t *testing.T) {
+	app, err := Launch("/bin/sh", []string{"-c", "echo out; echo err >&2; exit 3"}, LaunchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	code, err := app.Wait(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code != 3 {
+		t.Errorf("exit code is %d, want 3", code)
+	}
+	if app.IsRunning() {
+		t.Error("exited app is running")
+	}
+	if stdout, stderr := app.Output(); stdout != "out\n" || stderr != "err\n" {
+		t.Errorf("output is %q, %q", stdout, stderr)
+	}
+
+	// The reaped process is forgotten, so that an app reusing its PID is not mistaken for it.
+	runningMutex.Lock()
+	_, ok := running[app.PID()]
+	runningMutex.Unlock()
+	if ok {
+		t.Error("reaped process is still registered")
+	}
+	if p := newApp(app.PID()).launchedProcess(); p != nil {
+		t.Error("app found by PID shares the reaped process")
+	}
+}
+
+func TestLaunchRunning(t *testing.T) {
+	app, err := Launch("/bin/sh", []string{"-c", "sleep 10"}, LaunchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer app.Kill()
+	// An app found by PID while the process runs shares it.
+	if p := newApp(app.PID()).launchedProcess(); p == nil || p != app.launchedProcess() {
+		t.Error("app found by PID does not share the running process")
+	}
+	if !app.IsRunning() {
+		t.Error("app is not running")
+	}
+	if err := app.Kill(); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if code, err := app.Wait(ctx); err != nil || code != -1 {
+		t.Errorf("Wait after Kill = %d, %v, want -1, nil", code, err)
+	}
+}
+
+func TestLaunchExitWithChildRunning(t *testing.T) {
+	// The child keeps stdout open after the app exits.
+	app, err := Launch("/bin/sh", []string{"-c", "echo started; sleep 10 & exit 0"}, LaunchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if code, err := app.Wait(ctx); err != nil || code != 0 {
+		t.Errorf("Wait = %d, %v, want 0, nil", code, err)
+	}
+	if stdout, _ := app.Output(); stdout != "started\n" {
+		t.Errorf("stdout is %q", stdout)
+	}
+}
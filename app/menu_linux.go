@@ -0,0 +1,9 @@
+package app
+
+// Menu is not available on Linux, where apps do not share a common menu API.
+type Menu struct {
+}
+
+func (menu *Menu) items() []*MenuItem {
+	return []*MenuItem{}
+}
@@ -0,0 +1,23 @@
+package app
+
+type MenuItem struct {
+}
+
+func (item *MenuItem) text() string {
+	return ""
+}
+
+func (item *MenuItem) click() {
+}
+
+func (item *MenuItem) sub() *Menu {
+	return nil
+}
+
+func (item *MenuItem) isEnabled() bool {
+	return false
+}
+
+func (item *MenuItem) isSelected() bool {
+	return false
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// Quit asks the app to quit politely by closing all of its windows, as their
+// close buttons do. The app may refuse or ask the user first, and on macOS
+// most apps keep running without windows. It returns the first error of
+// closing a window.
+func (app *App) Quit() error {
+	var first error
+	for _, w := range app.windows() {
+		if err := w.close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Kill terminates the app immediately.
+func (app *App) Kill() error {
+	if p := app.launchedProcess(); p != nil {
+		return p.cmd.Process.Kill()
+	}
+	proc, err := os.FindProcess(int(app.pid))
+	if err != nil {
+		return err
+	}
+	return proc.Kill()
+}
+
+// IsRunning reports whether the process of the app has not exited.
+func (app *App) IsRunning() bool {
+	if p := app.launchedProcess(); p != nil {
+		select {
+		case <-p.done:
+			return false
+		default:
+			return true
+		}
+	}
+	return isRunning(app.pid)
+}
+
+// exitPollInterval is the interval Wait checks whether an app not started by Launch has exited.
+const exitPollInterval = 100 * time.Millisecond
+
+// Wait blocks until the app exits, and returns its exit code. The code is
+// known only for apps started by Launch which exited normally; it is -1
+// otherwise. When ctx is done first, it returns ctx.Err().
+func (app *App) Wait(ctx context.Context) (int, error) {
+	if p := app.launchedProcess(); p != nil {
+		select {
+		case <-p.done:
+			return p.cmd.ProcessState.ExitCode(), nil
+		case <-ctx.Done():
+			return -1, ctx.Err()
+		}
+	}
+	ticker := time.NewTicker(exitPollInterval)
+	defer ticker.Stop()
+	for isRunning(app.pid) {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return -1, ctx.Err()
+		}
+	}
+	return -1, nil
+}
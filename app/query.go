@@ -0,0 +1,137 @@
+package app
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Filter selects apps in Query. Zero fields match any app.
+type Filter struct {
+	// NameRegex is a regular expression which the base name of the executable file must contain a match of, as in Find.
+	NameRegex string
+	// PathRegex is a regular expression matched against the absolute path of the executable file.
+	PathRegex string
+	// CmdlineRegex is a regular expression matched against the command line arguments joined by spaces.
+	CmdlineRegex string
+	// User is the name or numeric id of the user owning the process. On Windows it may be qualified by the domain, as in "DOMAIN\user".
+	User string
+	// ParentPID selects children of the process.
+	ParentPID PID
+	// HasWindows selects apps which have at least one window.
+	HasWindows bool
+}
+
+// compile compiles a regular expression, which is nil when s is empty.
+func compile(s string) (*regexp.Regexp, error) {
+	if s == "" {
+		return nil, nil
+	}
+	return regexp.Compile(s)
+}
+
+// Query returns the running apps matching f, sorted by PID. It fails when a regular expression of f is invalid.
+func Query(f Filter) ([]*App, error) {
+	name, err := compile(f.NameRegex)
+	if err != nil {
+		return nil, err
+	}
+	path, err := compile(f.PathRegex)
+	if err != nil {
+		return nil, err
+	}
+	args, err := compile(f.CmdlineRegex)
+	if err != nil {
+		return nil, err
+	}
+	var parents map[PID]PID
+	if f.ParentPID != 0 {
+		parents = parentPIDs()
+	}
+	// Listing windows is expensive, so list them once rather than per app.
+	var windowPIDs map[PID]bool
+	if f.HasWindows {
+		windowPIDs = map[PID]bool{}
+		for _, w := range allWindows() {
+			windowPIDs[w.pid()] = true
+		}
+	}
+
+	apps := []*App{}
+	for _, pid := range ps() {
+		pid := PID(pid)
+		if parents != nil && parents[pid] != f.ParentPID {
+			continue
+		}
+		if windowPIDs != nil && !windowPIDs[pid] {
+			continue
+		}
+		app := newApp(pid)
+		if name != nil || path != nil {
+			p := app.path()
+			if p == "" {
+				continue
+			}
+			if name != nil && !name.MatchString(filepath.Base(p)) {
+				continue
+			}
+			if path != nil && !path.MatchString(p) {
+				continue
+			}
+		}
+		if args != nil && !args.MatchString(strings.Join(cmdline(pid), " ")) {
+			continue
+		}
+		if f.User != "" && !matchUser(pid, f.User) {
+			continue
+		}
+		apps = append(apps, app)
+	}
+	sort.Slice(apps, func(i, j int) bool { return apps[i].pid < apps[j].pid })
+	return apps, nil
+}
+
+// matchUser reports whether the process is owned by user, given as a name or an id.
+func matchUser(pid PID, user string) bool {
+	name, id, ok := processUser(pid)
+	if !ok {
+		return false
+	}
+	if user == id || strings.EqualFold(user, name) {
+		return true
+	}
+	// Allow names without the domain on Windows.
+	if i := strings.LastIndex(name, `\`); i >= 0 {
+		return strings.EqualFold(user, name[i+1:])
+	}
+	return false
+}
+
+// Cmdline returns the command line arguments of the app, including the
+// program name. It is empty when they cannot be read, e.g. for processes of
+// other users on macOS.
+func (app *App) Cmdline() []string {
+	return cmdline(app.pid)
+}
+
+// Parent returns the app which started the app, or nil when it is unknown or has exited.
+func (app *App) Parent() *App {
+	ppid, ok := parentPIDs()[app.pid]
+	if !ok || ppid == 0 {
+		return nil
+	}
+	return newApp(ppid)
+}
+
+// Children returns the apps started by the app which are running, sorted by PID.
+func (app *App) Children() []*App {
+	children := []*App{}
+	for pid, ppid := range parentPIDs() {
+		if ppid == app.pid && pid != app.pid {
+			children = append(children, newApp(pid))
+		}
+	}
+	sort.Slice(children, func(i, j int) bool { return children[i].pid < children[j].pid })
+	return children
+}
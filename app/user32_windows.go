@@ -0,0 +1,102 @@
+package app
+
+import (
+	"syscall"
+)
+
+// Functions which github.com/kbinani/win does not provide.
+var (
+	user32                     = syscall.NewLazyDLL("user32.dll")
+	procClientToScreen         = user32.NewProc("ClientToScreen")
+	procGetClientRect          = user32.NewProc("GetClientRect")
+	procGetClassName           = user32.NewProc("GetClassNameW")
+	procGetForegroundWindow    = user32.NewProc("GetForegroundWindow")
+	procGetWindowLong          = user32.NewProc("GetWindowLongW")
+	procGetWindowTextLength    = user32.NewProc("GetWindowTextLengthW")
+	procGetWindowRect          = user32.NewProc("GetWindowRect")
+	procGetWindowText          = user32.NewProc("GetWindowTextW")
+	procIsIconic               = user32.NewProc("IsIconic")
+	procIsZoomed               = user32.NewProc("IsZoomed")
+	procIsWindowEnabled        = user32.NewProc("IsWindowEnabled")
+	procSetWindowPos           = user32.NewProc("SetWindowPos")
+	procGetGUIThreadInfo       = user32.NewProc("GetGUIThreadInfo")
+	procChildWindowFromPointEx = user32.NewProc("ChildWindowFromPointEx")
+	procScreenToClient         = user32.NewProc("ScreenToClient")
+	procMapVirtualKey          = user32.NewProc("MapVirtualKeyW")
+	procPrintWindow            = user32.NewProc("PrintWindow")
+
+	kernel32               = syscall.NewLazyDLL("kernel32.dll")
+	procOpenProcess        = kernel32.NewProc("OpenProcess")
+	procGetExitCodeProcess = kernel32.NewProc("GetExitCodeProcess")
+
+	ntdll                         = syscall.NewLazyDLL("ntdll.dll")
+	procNtQueryInformationProcess = ntdll.NewProc("NtQueryInformationProcess")
+
+	dwmapi                    = syscall.NewLazyDLL("dwmapi.dll")
+	procDwmGetWindowAttribute = dwmapi.NewProc("DwmGetWindowAttribute")
+)
+
+const (
+	dwmwaCloaked = 14
+
+	gwlExStyle     = -20
+	wsExTopmost    = 0x00000008
+	wsExToolWindow = 0x00000080
+
+	swMaximize = 3
+	wmClose    = 0x0010
+
+	swpNoSize     = 0x0001
+	swpNoMove     = 0x0002
+	swpNoZOrder   = 0x0004
+	swpNoActivate = 0x0010
+
+	// pwRenderFullContent makes PrintWindow capture content rendered by DirectComposition, e.g. of browsers.
+	pwRenderFullContent = 0x00000002
+
+	wmKeyDown     = 0x0100
+	wmKeyUp       = 0x0101
+	wmChar        = 0x0102
+	wmSysKeyDown  = 0x0104
+	wmSysKeyUp    = 0x0105
+	wmMouseMove   = 0x0200
+	wmLButtonDown = 0x0201
+	wmLButtonUp   = 0x0202
+	mkLButton     = 0x0001
+
+	cwpSkipInvisible   = 0x0001
+	cwpSkipTransparent = 0x0004
+	mapvkVKToVSC       = 0
+
+	processQueryLimitedInformation = 0x1000
+	// stillActive is the exit code of a process which has not exited.
+	stillActive = 259
+	// processCommandLineInformation is a PROCESSINFOCLASS of NtQueryInformationProcess.
+	processCommandLineInformation = 60
+)
+
+// Values of hWndInsertAfter of SetWindowPos.
+const (
+	hwndTopmost   = ^uintptr(0) // -1
+	hwndNoTopmost = ^uintptr(1) // -2
+)
+
+type rect struct {
+	Left, Top, Right, Bottom int32
+}
+
+type point struct {
+	X, Y int32
+}
+
+type guiThreadInfo struct {
+	cbSize        uint32
+	flags         uint32
+	hwndActive    uintptr
+	hwndFocus     uintptr
+	hwndCapture   uintptr
+	hwndMenuOwner uintptr
+	hwndMoveSize  uintptr
+	hwndCaret     uintptr
+	rcCaret       rect
+}
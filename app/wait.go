@@ -0,0 +1,155 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Match describes windows awaited by WaitForWindow. Zero fields match any window.
+type Match struct {
+	// TitleRegex is a regular expression which the title must contain a match of.
+	TitleRegex string
+	// Class is the class of the window, as returned by Window.Class.
+	Class string
+	PID   PID
+	Role  WindowType
+	// Modal requires the window to be modal.
+	Modal bool
+}
+
+func (m Match) String() string {
+	conds := []string{}
+	if m.TitleRegex != "" {
+		conds = append(conds, fmt.Sprintf("title =~ %q", m.TitleRegex))
+	}
+	if m.Class != "" {
+		conds = append(conds, fmt.Sprintf("class %q", m.Class))
+	}
+	if m.PID != 0 {
+		conds = append(conds, fmt.Sprintf("pid %d", m.PID))
+	}
+	if m.Role != 0 {
+		conds = append(conds, fmt.Sprintf("type %v", m.Role))
+	}
+	if m.Modal {
+		conds = append(conds, "modal")
+	}
+	if len(conds) == 0 {
+		return "any window"
+	}
+	return "window with " + strings.Join(conds, ", ")
+}
+
+// matcher is a Match with the regular expression compiled.
+type matcher struct {
+	Match
+	title *regexp.Regexp
+}
+
+func (m *matcher) matches(w *Window) bool {
+	if m.PID != 0 && w.pid() != m.PID {
+		return false
+	}
+	if m.Class != "" && w.class() != m.Class {
+		return false
+	}
+	if m.Role != 0 && w.windowType() != m.Role {
+		return false
+	}
+	if m.Modal && !w.state().Has(Modal) {
+		return false
+	}
+	return m.title == nil || m.title.MatchString(w.title())
+}
+
+// WindowTimeoutError is returned by WaitForWindow when ctx is done before a matching window appears.
+type WindowTimeoutError struct {
+	Match Match
+	// Windows describes the windows which existed when waiting ended, one per line.
+	Windows []string
+	// Err is the error of the context.
+	Err error
+}
+
+func (e *WindowTimeoutError) Error() string {
+	msg := fmt.Sprintf("app: waiting for %v: %v", e.Match, e.Err)
+	if len(e.Windows) == 0 {
+		return msg + "; no windows exist"
+	}
+	return msg + "; existing windows:\n\t" + strings.Join(e.Windows, "\n\t")
+}
+
+func (e *WindowTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// describe returns a summary of w for error messages.
+func describe(w *Window) string {
+	desc := fmt.Sprintf("%q class %q pid %d type %v", w.title(), w.class(), w.pid(), w.windowType())
+	if w.state().Has(Modal) {
+		desc += " modal"
+	}
+	return desc
+}
+
+// WaitForWindow blocks until a top-level window matching m exists, and returns it.
+func WaitForWindow(ctx context.Context, m Match) (*Window, error) {
+	return waitForWindow(ctx, m, allWindows)
+}
+
+func waitForWindow(ctx context.Context, m Match, windows func() []*Window) (*Window, error) {
+	mt := &matcher{Match: m}
+	if m.TitleRegex != "" {
+		r, err := regexp.Compile(m.TitleRegex)
+		if err != nil {
+			return nil, err
+		}
+		mt.title = r
+	}
+	find := func() *Window {
+		for _, w := range windows() {
+			if mt.matches(w) {
+				return w
+			}
+		}
+		return nil
+	}
+	// Window events wake the wait up as soon as windows change. WatchWindows
+	// polls by itself where the window system does not report changes, and
+	// watching starts before the first look, so that no window is missed.
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events, err := watchWindows(watchCtx)
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	var poll <-chan time.Time
+	if err != nil || m.Modal {
+		// Poll when watching is unavailable, and for modality, whose changes are not reported as window events.
+		poll = ticker.C
+	}
+	if w := find(); w != nil {
+		return w, nil
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			e := &WindowTimeoutError{Match: m, Windows: []string{}, Err: ctx.Err()}
+			for _, w := range windows() {
+				e.Windows = append(e.Windows, describe(w))
+			}
+			return nil, e
+		case _, ok := <-events:
+			if !ok {
+				// Watching failed, so poll instead.
+				events, poll = nil, ticker.C
+			}
+		case <-poll:
+		}
+		if w := find(); w != nil {
+			return w, nil
+		}
+	}
+}
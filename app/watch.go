@@ -0,0 +1,123 @@
+package app
+
+import (
+	"context"
+	"image"
+	"time"
+)
+
+// WindowEventKind represents what happened to a window.
+type WindowEventKind int
+
+// Window event kinds.
+const (
+	WindowCreated WindowEventKind = iota
+	WindowDestroyed
+	WindowFocused
+	WindowTitleChanged
+	// WindowMoved is sent when a window is moved or resized.
+	WindowMoved
+	WindowMinimized
+)
+
+// WindowEvent is sent by WatchWindows.
+type WindowEvent struct {
+	Kind   WindowEventKind
+	Window *Window
+	Time   time.Time
+}
+
+// WatchWindows sends events of top-level windows of all apps to the returned
+// channel, which is closed when ctx is done or watching fails. On X11 under
+// a window manager supporting _NET_CLIENT_LIST, events are reported by the X
+// server; otherwise windows are polled, so short-lived changes may be missed. Methods of the Window of a
+// WindowDestroyed event fail or return zero values.
+func WatchWindows(ctx context.Context) (<-chan WindowEvent, error) {
+	return watchWindows(ctx)
+}
+
+// pollInterval is the interval of watchByPolling.
+const pollInterval = 250 * time.Millisecond
+
+// windowSnapshot is the state of a window compared by watchByPolling.
+type windowSnapshot struct {
+	window    *Window
+	title     string
+	bounds    image.Rectangle
+	minimized bool
+}
+
+func takeSnapshots() []windowSnapshot {
+	windows := allWindows()
+	snapshots := make([]windowSnapshot, len(windows))
+	for i, w := range windows {
+		bounds, _ := w.bounds()
+		snapshots[i] = windowSnapshot{window: w, title: w.title(), bounds: bounds, minimized: w.isMinimized()}
+	}
+	return snapshots
+}
+
+func findSnapshot(snapshots []windowSnapshot, w *Window) (windowSnapshot, bool) {
+	for _, s := range snapshots {
+		if s.window.same(w) {
+			return s, true
+		}
+	}
+	return windowSnapshot{}, false
+}
+
+// watchByPolling implements WatchWindows by comparing snapshots of windows.
+func watchByPolling(ctx context.Context) (<-chan WindowEvent, error) {
+	prev := takeSnapshots()
+	focused, _ := focusedWindow()
+	c := make(chan WindowEvent)
+	go func() {
+		defer close(c)
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			now := time.Now()
+			events := []WindowEvent{}
+			current := takeSnapshots()
+			for _, s := range current {
+				old, ok := findSnapshot(prev, s.window)
+				if !ok {
+					events = append(events, WindowEvent{Kind: WindowCreated, Window: s.window, Time: now})
+					continue
+				}
+				if s.title != old.title {
+					events = append(events, WindowEvent{Kind: WindowTitleChanged, Window: s.window, Time: now})
+				}
+				if s.bounds != old.bounds {
+					events = append(events, WindowEvent{Kind: WindowMoved, Window: s.window, Time: now})
+				}
+				if s.minimized && !old.minimized {
+					events = append(events, WindowEvent{Kind: WindowMinimized, Window: s.window, Time: now})
+				}
+			}
+			for _, s := range prev {
+				if _, ok := findSnapshot(current, s.window); !ok {
+					events = append(events, WindowEvent{Kind: WindowDestroyed, Window: s.window, Time: now})
+				}
+			}
+			prev = current
+			if w, err := focusedWindow(); err == nil && (focused == nil || !w.same(focused)) {
+				events = append(events, WindowEvent{Kind: WindowFocused, Window: w, Time: now})
+				focused = w
+			}
+			for _, e := range events {
+				select {
+				case c <- e:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return c, nil
+}
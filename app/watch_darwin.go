@@ -0,0 +1,9 @@
+package app
+
+import (
+	"context"
+)
+
+func watchWindows(ctx context.Context) (<-chan WindowEvent, error) {
+	return watchByPolling(ctx)
+}
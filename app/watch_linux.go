@@ -0,0 +1,173 @@
+package app
+
+/*
+#cgo LDFLAGS: -lX11
+#include <poll.h>
+#include <X11/Xlib.h>
+
+typedef struct {
+	int type;
+	// event is the window the event was selected on, and window the window it is about.
+	Window event;
+	Window window;
+	Atom atom;
+} window_event;
+
+// next_window_event waits up to timeout_ms for an event. It returns 1 when out is filled, 0 on timeout and -1 on failure.
+static int next_window_event(Display *dpy, int timeout_ms, window_event *out) {
+	if (!XPending(dpy)) {
+		struct pollfd pfd = { ConnectionNumber(dpy), POLLIN, 0 };
+		int ret = poll(&pfd, 1, timeout_ms);
+		if (ret < 0) {
+			return -1;
+		}
+		if (ret == 0 || !XPending(dpy)) {
+			return 0;
+		}
+	}
+	XEvent ev;
+	XNextEvent(dpy, &ev);
+	out->type = ev.type;
+	out->event = ev.xany.window;
+	out->window = ev.xany.window;
+	out->atom = None;
+	switch (ev.type) {
+	case PropertyNotify:
+		out->atom = ev.xproperty.atom;
+		break;
+	case ConfigureNotify:
+		out->window = ev.xconfigure.window;
+		break;
+	case DestroyNotify:
+		out->window = ev.xdestroywindow.window;
+		break;
+	}
+	return 1;
+}
+*/
+import "C"
+
+import (
+	"context"
+	"github.com/kbinani/robot/internal/x11"
+	"time"
+)
+
+// clientEventMask selects the events of client windows watched by watchWindows.
+const clientEventMask = C.PropertyChangeMask | C.StructureNotifyMask
+
+// watchWindows watches PropertyNotify of _NET_CLIENT_LIST and _NET_ACTIVE_WINDOW
+// on the root window, and property and structure changes of each client. It
+// polls when the window manager does not support _NET_CLIENT_LIST.
+func watchWindows(ctx context.Context) (<-chan WindowEvent, error) {
+	shared, err := openDisplay()
+	if err != nil {
+		return nil, err
+	}
+	xMutex.Lock()
+	if !supported(shared, "_NET_CLIENT_LIST") {
+		xMutex.Unlock()
+		// Without a window manager announcing its clients, allWindows lists mapped children of the root window instead.
+		return watchByPolling(ctx)
+	}
+	// Atoms are common to all connections.
+	clientList := atom(shared, "_NET_CLIENT_LIST")
+	activeWindow := atom(shared, "_NET_ACTIVE_WINDOW")
+	titleAtoms := map[C.Atom]bool{atom(shared, "_NET_WM_NAME"): true, atom(shared, "WM_NAME"): true}
+	stateAtoms := map[C.Atom]bool{atom(shared, "_NET_WM_STATE"): true, atom(shared, "WM_STATE"): true}
+	xMutex.Unlock()
+
+	// Events are read in a blocking loop, so use a connection separate from the shared one.
+	private, err := x11.OpenPrivate()
+	if err != nil {
+		return nil, err
+	}
+	dpy := (*C.Display)(private)
+	root := C.XDefaultRootWindow(dpy)
+	C.XSelectInput(dpy, root, C.PropertyChangeMask)
+	clients := map[C.Window]windowSnapshot{}
+	track := func(w *Window) {
+		C.XSelectInput(dpy, w.id, clientEventMask)
+		bounds, _ := w.bounds()
+		clients[w.id] = windowSnapshot{window: w, title: w.title(), bounds: bounds, minimized: w.isMinimized()}
+	}
+	for _, w := range allWindows() {
+		track(w)
+	}
+	C.XFlush(dpy)
+
+	c := make(chan WindowEvent)
+	go func() {
+		defer close(c)
+		defer C.XCloseDisplay(dpy)
+		var ev C.window_event
+		for ctx.Err() == nil {
+			n := C.next_window_event(dpy, 100, &ev)
+			if n < 0 {
+				return
+			}
+			if n == 0 {
+				continue
+			}
+			now := time.Now()
+			events := []WindowEvent{}
+			s, tracked := clients[ev.window]
+			switch {
+			case ev._type == C.PropertyNotify && ev.window == root && ev.atom == clientList:
+				current := map[C.Window]bool{}
+				for _, w := range allWindows() {
+					current[w.id] = true
+					if _, ok := clients[w.id]; !ok {
+						track(w)
+						events = append(events, WindowEvent{Kind: WindowCreated, Window: w, Time: now})
+					}
+				}
+				for id, s := range clients {
+					if !current[id] {
+						delete(clients, id)
+						events = append(events, WindowEvent{Kind: WindowDestroyed, Window: s.window, Time: now})
+					}
+				}
+				C.XFlush(dpy)
+			case ev._type == C.PropertyNotify && ev.window == root && ev.atom == activeWindow:
+				if w, err := focusedWindow(); err == nil {
+					events = append(events, WindowEvent{Kind: WindowFocused, Window: w, Time: now})
+				}
+			case !tracked:
+			case ev._type == C.PropertyNotify && titleAtoms[ev.atom]:
+				// Apps often set both _NET_WM_NAME and WM_NAME, so report actual changes only.
+				if title := s.window.title(); title != s.title {
+					s.title = title
+					events = append(events, WindowEvent{Kind: WindowTitleChanged, Window: s.window, Time: now})
+				}
+			case ev._type == C.PropertyNotify && stateAtoms[ev.atom]:
+				minimized := s.window.isMinimized()
+				if minimized && !s.minimized {
+					events = append(events, WindowEvent{Kind: WindowMinimized, Window: s.window, Time: now})
+				}
+				s.minimized = minimized
+			case ev._type == C.ConfigureNotify && ev.event == ev.window:
+				// ConfigureNotify is also sent for restacking, which keeps the bounds.
+				if bounds, err := s.window.bounds(); err == nil && bounds != s.bounds {
+					s.bounds = bounds
+					events = append(events, WindowEvent{Kind: WindowMoved, Window: s.window, Time: now})
+				}
+			case ev._type == C.DestroyNotify:
+				// The window manager updates _NET_CLIENT_LIST later, which finds nothing to report then.
+				delete(clients, ev.window)
+				events = append(events, WindowEvent{Kind: WindowDestroyed, Window: s.window, Time: now})
+			}
+			if _, ok := clients[ev.window]; ok && tracked {
+				clients[ev.window] = s
+			}
+			for _, e := range events {
+				select {
+				case c <- e:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return c, nil
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"context"
+	"github.com/kbinani/robot/internal/xvfb"
+	"image"
+	"testing"
+	"time"
+)
+
+// expectEvent reads events from c until one of kind about a window titled title arrives.
+func expectEvent(t *testing.T, c <-chan WindowEvent, kind WindowEventKind, title string) {
+	t.Helper()
+	timeout := time.After(3 * time.Second)
+	for {
+		select {
+		case e, ok := <-c:
+			if !ok {
+				t.Fatal("channel closed")
+			}
+			if e.Kind == kind && (title == "" || e.Window.Title() == title) {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("no event %d about %q", kind, title)
+		}
+	}
+}
+
+func testWatchWindows(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c, err := WatchWindows(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, w := newTestWindow(t, xvfb.WindowOptions{Title: "watched"})
+	expectEvent(t, c, WindowCreated, "watched")
+	b, err := w.Bounds()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.SetBounds(b.Add(image.Pt(20, 0))); err != nil {
+		t.Fatal(err)
+	}
+	expectEvent(t, c, WindowMoved, "watched")
+	client.Close()
+	expectEvent(t, c, WindowDestroyed, "")
+}
+
+func TestWatchWindowsWithoutWM(t *testing.T) {
+	needX(t)
+	testWatchWindows(t)
+}
+
+func TestWatchWindowsEWMH(t *testing.T) {
+	needX(t)
+	startWM(t, xvfb.EWMH...)
+	testWatchWindows(t)
+}
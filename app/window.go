@@ -1,5 +1,62 @@
 package app
 
+import (
+	"errors"
+	"fmt"
+	"image"
+)
+
+// ErrNotSupported is returned by window operations which the platform, or its window manager, does not provide.
+var ErrNotSupported = errors.New("app: not supported on this platform")
+
+// WindowType represents the kind of a window.
+type WindowType int
+
+// Window types, following _NET_WM_WINDOW_TYPE of EWMH. The zero WindowType
+// is not a type of any window, and matches every type in Match.
+const (
+	NormalWindow WindowType = iota + 1
+	DialogWindow
+	UtilityWindow
+	ToolbarWindow
+	MenuWindow
+	SplashWindow
+	DockWindow
+	DesktopWindow
+	NotificationWindow
+)
+
+var windowTypeNames = []string{"", "normal", "dialog", "utility", "toolbar", "menu", "splash", "dock", "desktop", "notification"}
+
+func (t WindowType) String() string {
+	if t < 0 || int(t) >= len(windowTypeNames) {
+		return fmt.Sprintf("WindowType(%d)", int(t))
+	}
+	return windowTypeNames[t]
+}
+
+// WindowState is a set of window states.
+type WindowState int
+
+// Window states, following _NET_WM_STATE of EWMH. Not every platform reports every state.
+const (
+	Modal WindowState = 1 << iota
+	Minimized
+	Maximized
+	Fullscreen
+	Above
+	Below
+	Sticky
+	Shaded
+	SkipTaskbar
+	DemandsAttention
+)
+
+// Has reports whether s contains all of states.
+func (s WindowState) Has(states WindowState) bool {
+	return s&states == states
+}
+
 func (w *Window) Activate() {
 	w.activate()
 }
@@ -11,3 +68,126 @@ func (w *Window) Minimize() {
 func (w *Window) IsMinimized() bool {
 	return w.isMinimized()
 }
+
+// Title returns the title of the window.
+func (w *Window) Title() string {
+	return w.title()
+}
+
+// Class returns the class of the window: the class part of WM_CLASS on X11,
+// the registered window class name on Windows, and the accessibility subrole
+// on macOS.
+func (w *Window) Class() string {
+	return w.class()
+}
+
+// PID returns the process id of the app owning the window, or 0 when it is unknown.
+func (w *Window) PID() PID {
+	return w.pid()
+}
+
+// Kind returns the kind of the window, such as DialogWindow.
+func (w *Window) Kind() WindowType {
+	return w.windowType()
+}
+
+// State returns the current states of the window.
+func (w *Window) State() WindowState {
+	return w.state()
+}
+
+// Bounds returns the frame of the window, including decorations, in screen coordinates.
+func (w *Window) Bounds() (image.Rectangle, error) {
+	return w.bounds()
+}
+
+// ClientBounds returns the client area of the window, which excludes
+// decorations, in screen coordinates. On macOS it equals Bounds.
+func (w *Window) ClientBounds() (image.Rectangle, error) {
+	return w.clientBounds()
+}
+
+// ClientArea is the client area of a window. It implements robot.Frame, so that positions can be relative to it.
+type ClientArea struct {
+	w *Window
+}
+
+// Client returns the client area of the window.
+func (w *Window) Client() ClientArea {
+	return ClientArea{w}
+}
+
+// Bounds returns the client area in screen coordinates, as Window.ClientBounds.
+func (c ClientArea) Bounds() (image.Rectangle, error) {
+	return c.w.clientBounds()
+}
+
+// Move moves the top-left corner of the window frame to p, keeping its size.
+func (w *Window) Move(p image.Point) error {
+	r, err := w.bounds()
+	if err != nil {
+		return err
+	}
+	return w.setBounds(r.Sub(r.Min).Add(p))
+}
+
+// Resize changes the size of the window frame to size, keeping its top-left corner.
+func (w *Window) Resize(size image.Point) error {
+	r, err := w.bounds()
+	if err != nil {
+		return err
+	}
+	return w.setBounds(image.Rectangle{r.Min, r.Min.Add(size)})
+}
+
+// SetBounds moves and resizes the window so that its frame, including
+// decorations, occupies r. A maximized or fullscreen window is restored
+// first. The window may end up smaller or larger when r violates its size
+// constraints, so check Bounds when exact placement matters.
+func (w *Window) SetBounds(r image.Rectangle) error {
+	return w.setBounds(r.Canon())
+}
+
+// Capture returns the contents of the window frame, without windows or
+// notifications covering it. The bounds of the image are those returned by
+// Bounds. Minimized windows cannot be captured. On X11 it requires the
+// Composite extension.
+func (w *Window) Capture() (*image.RGBA, error) {
+	return w.capture()
+}
+
+// Maximize maximizes the window.
+func (w *Window) Maximize() error {
+	return w.maximize()
+}
+
+// Restore restores a minimized, maximized or fullscreen window to its normal state.
+func (w *Window) Restore() error {
+	return w.restore()
+}
+
+// Fullscreen makes the window cover its whole display without decorations.
+func (w *Window) Fullscreen() error {
+	return w.fullscreen()
+}
+
+// Close asks the window to close, as the close button of its frame does. The
+// app may refuse, e.g. to ask for saving changes.
+func (w *Window) Close() error {
+	return w.close()
+}
+
+// IsVisible reports whether the window is shown on screen, i.e. mapped and not minimized. It may still be covered by other windows.
+func (w *Window) IsVisible() bool {
+	return w.isVisible()
+}
+
+// IsFocused reports whether the window has the keyboard focus.
+func (w *Window) IsFocused() bool {
+	return w.isFocused()
+}
+
+// SetAlwaysOnTop keeps the window above other windows, or stops doing so.
+func (w *Window) SetAlwaysOnTop(on bool) error {
+	return w.setAlwaysOnTop(on)
+}
@@ -1,7 +1,9 @@
 package app
 
 import (
+	"errors"
 	"github.com/kbinani/robot/ax"
+	"image"
 )
 
 type Window struct {
@@ -33,3 +35,128 @@ func (w *Window) minimize() {
 func (w *Window) isMinimized() bool {
 	return w.axWindow.IsMinimized()
 }
+
+func (w *Window) title() string {
+	return w.axWindow.Title()
+}
+
+func (w *Window) class() string {
+	return w.axWindow.Subrole()
+}
+
+func (w *Window) windowType() WindowType {
+	switch w.axWindow.Subrole() {
+	case ax.DialogSubrole, ax.SystemDialogSubrole:
+		return DialogWindow
+	case ax.FloatingWindowSubrole, ax.SystemFloatingWindowSubrole:
+		return UtilityWindow
+	}
+	return NormalWindow
+}
+
+func (w *Window) state() WindowState {
+	var s WindowState
+	if w.axWindow.IsModal() {
+		s |= Modal
+	}
+	if w.axWindow.IsMinimized() {
+		s |= Minimized
+	}
+	if fullscreen, _ := w.axWindow.BoolAttr("AXFullScreen"); fullscreen {
+		s |= Fullscreen
+	}
+	return s
+}
+
+func (w *Window) bounds() (image.Rectangle, error) {
+	pos, err := w.axWindow.PointAttr(ax.PositionAttribute)
+	if err != nil {
+		return image.Rectangle{}, err
+	}
+	size, err := w.axWindow.SizeAttr(ax.SizeAttribute)
+	if err != nil {
+		return image.Rectangle{}, err
+	}
+	return image.Rect(int(pos.X), int(pos.Y), int(pos.X+size.Dx), int(pos.Y+size.Dy)), nil
+}
+
+// clientBounds returns the bounds, since the accessibility API does not tell the size of the title bar.
+func (w *Window) clientBounds() (image.Rectangle, error) {
+	return w.bounds()
+}
+
+func (w *Window) setBounds(r image.Rectangle) error {
+	if err := w.restore(); err != nil {
+		return err
+	}
+	if err := w.axWindow.SetPointAttr(ax.PositionAttribute, ax.Point{X: float64(r.Min.X), Y: float64(r.Min.Y)}); err != nil {
+		return err
+	}
+	if err := w.axWindow.SetSizeAttr(ax.SizeAttribute, ax.Size{Dx: float64(r.Dx()), Dy: float64(r.Dy())}); err != nil {
+		return err
+	}
+	// The size may be constrained by the position, e.g. near the bottom of the display, so move again.
+	return w.axWindow.SetPointAttr(ax.PositionAttribute, ax.Point{X: float64(r.Min.X), Y: float64(r.Min.Y)})
+}
+
+// press presses the title bar button of the window with the subrole.
+func (w *Window) press(subrole string) error {
+	for _, child := range w.axWindow.Children() {
+		if child.Role() == ax.ButtonRole && child.Subrole() == subrole {
+			child.Perform(ax.PressAction)
+			return nil
+		}
+	}
+	return errors.New("app: window has no " + subrole)
+}
+
+// maximize zooms the window, which fills the display for most apps.
+func (w *Window) maximize() error {
+	return w.press(ax.ZoomButtonSubrole)
+}
+
+func (w *Window) restore() error {
+	if fullscreen, _ := w.axWindow.BoolAttr("AXFullScreen"); fullscreen {
+		if err := w.axWindow.SetBoolAttr("AXFullScreen", false); err != nil {
+			return err
+		}
+	}
+	if w.axWindow.IsMinimized() {
+		return w.axWindow.SetBoolAttr(ax.MinimizedAttribute, false)
+	}
+	return nil
+}
+
+func (w *Window) fullscreen() error {
+	return w.axWindow.SetBoolAttr("AXFullScreen", true)
+}
+
+func (w *Window) close() error {
+	return w.press(ax.CloseButtonSubrole)
+}
+
+func (w *Window) isVisible() bool {
+	return !w.axWindow.IsMinimized()
+}
+
+func (w *Window) isFocused() bool {
+	app := ax.CreateSystemWide().FocusedApplication()
+	if app == nil {
+		return false
+	}
+	return w.axWindow.Equal(app.FocusedWindow())
+}
+
+// setAlwaysOnTop is not supported, since the accessibility API cannot change window levels of other apps.
+func (w *Window) setAlwaysOnTop(on bool) error {
+	return ErrNotSupported
+}
+
+// same reports whether w and other are the same window.
+func (w *Window) same(other *Window) bool {
+	return w.axWindow.Equal(other.axWindow)
+}
+
+func (w *Window) pid() PID {
+	return PID(w.axWindow.PID())
+}
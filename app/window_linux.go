@@ -0,0 +1,467 @@
+package app
+
+/*
+#include <X11/Xlib.h>
+#include <X11/Xatom.h>
+#include <X11/Xutil.h>
+
+// send_protocol_message sends a WM_PROTOCOLS message to the client w itself.
+static void send_protocol_message(Display *dpy, Window w, Atom protocols, Atom protocol) {
+	XEvent ev = {0};
+	ev.xclient.type = ClientMessage;
+	ev.xclient.window = w;
+	ev.xclient.message_type = protocols;
+	ev.xclient.format = 32;
+	ev.xclient.data.l[0] = protocol;
+	ev.xclient.data.l[1] = CurrentTime;
+	XSendEvent(dpy, w, False, NoEventMask, &ev);
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"image"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+type Window struct {
+	id C.Window
+}
+
+func newWindow(id C.Window) *Window {
+	w := new(Window)
+	w.id = id
+	return w
+}
+
+// allWindows returns the windows managed by the window manager, in the order
+// of _NET_CLIENT_LIST. Without a window manager supporting it, e.g. on a bare
+// Xvfb, it returns the mapped children of the root window instead.
+func allWindows() []*Window {
+	dpy, err := openDisplay()
+	if err != nil {
+		return []*Window{}
+	}
+	xMutex.Lock()
+	defer xMutex.Unlock()
+	ret := []*Window{}
+	if !supported(dpy, "_NET_CLIENT_LIST") {
+		for _, id := range mappedChildren(dpy, rootWindow(dpy)) {
+			ret = append(ret, newWindow(id))
+		}
+		return ret
+	}
+	for _, id := range windowsProperty(dpy, rootWindow(dpy), "_NET_CLIENT_LIST") {
+		ret = append(ret, newWindow(id))
+	}
+	return ret
+}
+
+// mappedChildren returns the children of w which are mapped and not override-redirect, such as menus, in stacking order. Callers must hold xMutex.
+func mappedChildren(dpy *C.Display, w C.Window) []C.Window {
+	var root, parent C.Window
+	var children *C.Window
+	var count C.uint
+	if C.XQueryTree(dpy, w, &root, &parent, &children, &count) == 0 {
+		takeXError()
+		return nil
+	}
+	if children == nil {
+		return nil
+	}
+	defer C.XFree(unsafe.Pointer(children))
+	ret := []C.Window{}
+	for _, id := range (*[1 << 28]C.Window)(unsafe.Pointer(children))[:count:count] {
+		var attr C.XWindowAttributes
+		if C.XGetWindowAttributes(dpy, id, &attr) == 0 {
+			takeXError()
+			continue
+		}
+		if attr.map_state == C.IsViewable && attr.override_redirect == 0 {
+			ret = append(ret, id)
+		}
+	}
+	return ret
+}
+
+func focusedWindow() (*Window, error) {
+	dpy, err := openDisplay()
+	if err != nil {
+		return nil, err
+	}
+	xMutex.Lock()
+	defer xMutex.Unlock()
+	if !supported(dpy, "_NET_ACTIVE_WINDOW") {
+		return nil, ErrNotSupported
+	}
+	active := windowsProperty(dpy, rootWindow(dpy), "_NET_ACTIVE_WINDOW")
+	if len(active) == 0 || active[0] == C.None {
+		return nil, errNoFocus
+	}
+	return newWindow(active[0]), nil
+}
+
+func frontmost() (*App, error) {
+	w, err := focusedWindow()
+	if err != nil {
+		return nil, err
+	}
+	pid := w.pid()
+	if pid == 0 {
+		return nil, errors.New("app: focused window does not tell its process")
+	}
+	return newApp(pid), nil
+}
+
+func (app *App) windows() []*Window {
+	ret := []*Window{}
+	for _, w := range allWindows() {
+		if w.pid() == app.pid {
+			ret = append(ret, w)
+		}
+	}
+	return ret
+}
+
+// pid returns _NET_WM_PID of the window, or 0 when it is not set.
+func (w *Window) pid() PID {
+	dpy, err := openDisplay()
+	if err != nil {
+		return 0
+	}
+	xMutex.Lock()
+	defer xMutex.Unlock()
+	pid, _ := cardinalProperty(dpy, w.id, "_NET_WM_PID")
+	return PID(pid)
+}
+
+func (w *Window) activate() {
+	dpy, err := openDisplay()
+	if err != nil {
+		return
+	}
+	xMutex.Lock()
+	defer xMutex.Unlock()
+	// Source indication 2 tells the window manager that the request comes from a pager, which it honours without focus stealing prevention.
+	sendClientMessage(dpy, w.id, "_NET_ACTIVE_WINDOW", 2, C.CurrentTime)
+}
+
+func (w *Window) minimize() {
+	dpy, err := openDisplay()
+	if err != nil {
+		return
+	}
+	xMutex.Lock()
+	defer xMutex.Unlock()
+	sendClientMessage(dpy, w.id, "WM_CHANGE_STATE", C.IconicState)
+}
+
+func (w *Window) isMinimized() bool {
+	return w.state().Has(Minimized)
+}
+
+func (w *Window) title() string {
+	dpy, err := openDisplay()
+	if err != nil {
+		return ""
+	}
+	xMutex.Lock()
+	defer xMutex.Unlock()
+	if title, ok := stringProperty(dpy, w.id, "_NET_WM_NAME"); ok {
+		return title
+	}
+	title, _ := stringProperty(dpy, w.id, "WM_NAME")
+	return title
+}
+
+func (w *Window) class() string {
+	dpy, err := openDisplay()
+	if err != nil {
+		return ""
+	}
+	xMutex.Lock()
+	defer xMutex.Unlock()
+	// WM_CLASS holds the instance name and the class name, each terminated by NUL.
+	s, _ := stringProperty(dpy, w.id, "WM_CLASS")
+	parts := strings.Split(s, "\x00")
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[1]
+}
+
+var windowTypes = map[string]WindowType{
+	"_NET_WM_WINDOW_TYPE_NORMAL":        NormalWindow,
+	"_NET_WM_WINDOW_TYPE_DIALOG":        DialogWindow,
+	"_NET_WM_WINDOW_TYPE_UTILITY":       UtilityWindow,
+	"_NET_WM_WINDOW_TYPE_TOOLBAR":       ToolbarWindow,
+	"_NET_WM_WINDOW_TYPE_MENU":          MenuWindow,
+	"_NET_WM_WINDOW_TYPE_DROPDOWN_MENU": MenuWindow,
+	"_NET_WM_WINDOW_TYPE_POPUP_MENU":    MenuWindow,
+	"_NET_WM_WINDOW_TYPE_SPLASH":        SplashWindow,
+	"_NET_WM_WINDOW_TYPE_DOCK":          DockWindow,
+	"_NET_WM_WINDOW_TYPE_DESKTOP":       DesktopWindow,
+	"_NET_WM_WINDOW_TYPE_NOTIFICATION":  NotificationWindow,
+}
+
+func (w *Window) windowType() WindowType {
+	dpy, err := openDisplay()
+	if err != nil {
+		return NormalWindow
+	}
+	xMutex.Lock()
+	defer xMutex.Unlock()
+	// Types are listed in order of preference; take the first one known.
+	for _, name := range atomsProperty(dpy, w.id, "_NET_WM_WINDOW_TYPE") {
+		if t, ok := windowTypes[name]; ok {
+			return t
+		}
+	}
+	// Transient windows without a type are dialogs, as EWMH specifies.
+	if _, ok := property(dpy, w.id, "WM_TRANSIENT_FOR", C.XA_WINDOW); ok {
+		return DialogWindow
+	}
+	return NormalWindow
+}
+
+var windowStates = map[string]WindowState{
+	"_NET_WM_STATE_MODAL":             Modal,
+	"_NET_WM_STATE_HIDDEN":            Minimized,
+	"_NET_WM_STATE_FULLSCREEN":        Fullscreen,
+	"_NET_WM_STATE_ABOVE":             Above,
+	"_NET_WM_STATE_BELOW":             Below,
+	"_NET_WM_STATE_STICKY":            Sticky,
+	"_NET_WM_STATE_SHADED":            Shaded,
+	"_NET_WM_STATE_SKIP_TASKBAR":      SkipTaskbar,
+	"_NET_WM_STATE_DEMANDS_ATTENTION": DemandsAttention,
+}
+
+func (w *Window) state() WindowState {
+	dpy, err := openDisplay()
+	if err != nil {
+		return 0
+	}
+	xMutex.Lock()
+	defer xMutex.Unlock()
+	var s WindowState
+	maximized := 0
+	for _, name := range atomsProperty(dpy, w.id, "_NET_WM_STATE") {
+		s |= windowStates[name]
+		if name == "_NET_WM_STATE_MAXIMIZED_VERT" || name == "_NET_WM_STATE_MAXIMIZED_HORZ" {
+			maximized++
+		}
+	}
+	if maximized == 2 {
+		s |= Maximized
+	}
+	// ICCCM WM_STATE tells iconic windows under window managers without _NET_WM_STATE_HIDDEN.
+	if items, ok := property(dpy, w.id, "WM_STATE", atom(dpy, "WM_STATE")); ok && len(items) > 0 && items[0] == C.IconicState {
+		s |= Minimized
+	}
+	return s
+}
+
+// frameExtents returns the widths of the decorations added by the window manager. Callers must hold xMutex.
+func (w *Window) frameExtents(dpy *C.Display) (left, right, top, bottom int) {
+	items, ok := property(dpy, w.id, "_NET_FRAME_EXTENTS", C.XA_CARDINAL)
+	if !ok || len(items) < 4 {
+		return 0, 0, 0, 0
+	}
+	return int(items[0]), int(items[1]), int(items[2]), int(items[3])
+}
+
+// frameBounds returns the bounds of the frame in root coordinates. Callers must hold xMutex.
+func (w *Window) frameBounds(dpy *C.Display) (image.Rectangle, error) {
+	var attr C.XWindowAttributes
+	if C.XGetWindowAttributes(dpy, w.id, &attr) == 0 {
+		takeXError()
+		return image.Rectangle{}, errors.New("app: window does not exist")
+	}
+	var x, y C.int
+	var child C.Window
+	C.XTranslateCoordinates(dpy, w.id, rootWindow(dpy), 0, 0, &x, &y, &child)
+	left, right, top, bottom := w.frameExtents(dpy)
+	return image.Rect(int(x)-left, int(y)-top, int(x+attr.width)+right, int(y+attr.height)+bottom), nil
+}
+
+func (w *Window) clientBounds() (image.Rectangle, error) {
+	dpy, err := openDisplay()
+	if err != nil {
+		return image.Rectangle{}, err
+	}
+	xMutex.Lock()
+	defer xMutex.Unlock()
+	r, err := w.frameBounds(dpy)
+	if err != nil {
+		return image.Rectangle{}, err
+	}
+	left, right, top, bottom := w.frameExtents(dpy)
+	return image.Rect(r.Min.X+left, r.Min.Y+top, r.Max.X-right, r.Max.Y-bottom), nil
+}
+
+func (w *Window) bounds() (image.Rectangle, error) {
+	dpy, err := openDisplay()
+	if err != nil {
+		return image.Rectangle{}, err
+	}
+	xMutex.Lock()
+	defer xMutex.Unlock()
+	return w.frameBounds(dpy)
+}
+
+// settleTimeout is how long setBounds waits for the window manager to apply a new geometry.
+const settleTimeout = 500 * time.Millisecond
+
+func (w *Window) setBounds(r image.Rectangle) error {
+	dpy, err := openDisplay()
+	if err != nil {
+		return err
+	}
+	xMutex.Lock()
+	if supported(dpy, "_NET_WM_STATE") {
+		w.changeState(dpy, 0, "_NET_WM_STATE_MAXIMIZED_VERT", "_NET_WM_STATE_MAXIMIZED_HORZ")
+		w.changeState(dpy, 0, "_NET_WM_STATE_FULLSCREEN", "")
+	}
+	left, right, top, bottom := w.frameExtents(dpy)
+	width, height := r.Dx()-left-right, r.Dy()-top-bottom
+	if width < 1 || height < 1 {
+		xMutex.Unlock()
+		return errors.New("app: bounds are smaller than the window frame")
+	}
+	if supported(dpy, "_NET_MOVERESIZE_WINDOW") {
+		// Flags hold the gravity in bits 0-7, which fields are present in bits 8-11, and the source indication in bits 12-13.
+		// With NorthWestGravity the position is that of the top-left corner of the frame.
+		const flags = C.NorthWestGravity | 0xf<<8 | 2<<12
+		sendClientMessage(dpy, w.id, "_NET_MOVERESIZE_WINDOW", flags, int64(r.Min.X), int64(r.Min.Y), int64(width), int64(height))
+	} else {
+		C.XMoveResizeWindow(dpy, w.id, C.int(r.Min.X), C.int(r.Min.Y), C.uint(width), C.uint(height))
+		C.XFlush(dpy)
+	}
+	xMutex.Unlock()
+
+	// The window manager applies the request asynchronously. Wait for it, so that coordinates are valid once this returns.
+	deadline := time.Now().Add(settleTimeout)
+	for time.Now().Before(deadline) {
+		b, err := w.bounds()
+		if err != nil {
+			return err
+		}
+		if b == r {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return nil
+}
+
+// changeState adds (action 1) or removes (action 0) up to two _NET_WM_STATE atoms. Callers must hold xMutex.
+func (w *Window) changeState(dpy *C.Display, action int64, first, second string) {
+	var a2 int64
+	if second != "" {
+		a2 = int64(atom(dpy, second))
+	}
+	sendClientMessage(dpy, w.id, "_NET_WM_STATE", action, int64(atom(dpy, first)), a2, 2)
+}
+
+// setState changes _NET_WM_STATE atoms, failing when the window manager does not support them.
+func (w *Window) setState(on bool, first, second string) error {
+	dpy, err := openDisplay()
+	if err != nil {
+		return err
+	}
+	xMutex.Lock()
+	defer xMutex.Unlock()
+	if !supported(dpy, "_NET_WM_STATE") || !supported(dpy, first) {
+		return ErrNotSupported
+	}
+	var action int64
+	if on {
+		action = 1
+	}
+	w.changeState(dpy, action, first, second)
+	return nil
+}
+
+func (w *Window) maximize() error {
+	return w.setState(true, "_NET_WM_STATE_MAXIMIZED_VERT", "_NET_WM_STATE_MAXIMIZED_HORZ")
+}
+
+func (w *Window) restore() error {
+	if w.isMinimized() {
+		w.activate()
+	}
+	// A window manager cannot have put the window into a state it does not support, so such states are skipped.
+	for _, states := range [][2]string{
+		{"_NET_WM_STATE_FULLSCREEN", ""},
+		{"_NET_WM_STATE_MAXIMIZED_VERT", "_NET_WM_STATE_MAXIMIZED_HORZ"},
+	} {
+		if err := w.setState(false, states[0], states[1]); err != nil && err != ErrNotSupported {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Window) fullscreen() error {
+	return w.setState(true, "_NET_WM_STATE_FULLSCREEN", "")
+}
+
+func (w *Window) setAlwaysOnTop(on bool) error {
+	return w.setState(on, "_NET_WM_STATE_ABOVE", "")
+}
+
+func (w *Window) close() error {
+	dpy, err := openDisplay()
+	if err != nil {
+		return err
+	}
+	xMutex.Lock()
+	defer xMutex.Unlock()
+	if supported(dpy, "_NET_CLOSE_WINDOW") {
+		sendClientMessage(dpy, w.id, "_NET_CLOSE_WINDOW", C.CurrentTime, 2)
+		return nil
+	}
+	// Without a window manager, ask the client directly as ICCCM specifies, if it takes part in the protocol.
+	for _, name := range atomsProperty(dpy, w.id, "WM_PROTOCOLS") {
+		if name == "WM_DELETE_WINDOW" {
+			C.send_protocol_message(dpy, w.id, atom(dpy, "WM_PROTOCOLS"), atom(dpy, "WM_DELETE_WINDOW"))
+			C.XFlush(dpy)
+			return nil
+		}
+	}
+	return ErrNotSupported
+}
+
+func (w *Window) isVisible() bool {
+	dpy, err := openDisplay()
+	if err != nil {
+		return false
+	}
+	xMutex.Lock()
+	var attr C.XWindowAttributes
+	ok := C.XGetWindowAttributes(dpy, w.id, &attr) != 0
+	if !ok {
+		takeXError()
+	}
+	xMutex.Unlock()
+	return ok && attr.map_state == C.IsViewable && !w.isMinimized()
+}
+
+func (w *Window) isFocused() bool {
+	dpy, err := openDisplay()
+	if err != nil {
+		return false
+	}
+	xMutex.Lock()
+	defer xMutex.Unlock()
+	active := windowsProperty(dpy, rootWindow(dpy), "_NET_ACTIVE_WINDOW")
+	return len(active) > 0 && active[0] == w.id
+}
+
+// same reports whether w and other are the same window.
+func (w *Window) same(other *Window) bool {
+	return w.id == other.id
+}
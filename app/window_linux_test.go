@@ -0,0 +1,239 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"github.com/kbinani/robot/internal/xvfb"
+	"image"
+	"image/color"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestWindowsWithoutWM(t *testing.T) {
+	needX(t)
+	_, w := newTestWindow(t, xvfb.WindowOptions{Title: "bare", Class: "Bare", PID: os.Getpid()})
+	if got := w.Class(); got != "Bare" {
+		t.Errorf("Class is %q, want Bare", got)
+	}
+	if got := w.PID(); got != PID(os.Getpid()) {
+		t.Errorf("PID is %d, want %d", got, os.Getpid())
+	}
+	if b, err := w.Bounds(); err != nil || b != image.Rect(100, 100, 300, 250) {
+		t.Errorf("Bounds are %v, %v", b, err)
+	}
+	if _, err := FocusedWindow(); err != ErrNotSupported {
+		t.Errorf("FocusedWindow without a WM = %v, want ErrNotSupported", err)
+	}
+	if err := w.Maximize(); err != ErrNotSupported {
+		t.Errorf("Maximize without a WM = %v, want ErrNotSupported", err)
+	}
+	if err := w.Restore(); err != nil {
+		t.Errorf("Restore without a WM = %v, want nil", err)
+	}
+}
+
+func TestWindowEWMH(t *testing.T) {
+	needX(t)
+	startWM(t, xvfb.EWMH...)
+	client, w := newTestWindow(t, xvfb.WindowOptions{Title: "managed", Class: "Managed", PID: os.Getpid(), DeleteWindow: true})
+
+	windows := newApp(PID(os.Getpid())).Windows()
+	if len(windows) != 1 || !windows[0].same(w) {
+		t.Errorf("Windows of this process are %v", windows)
+	}
+
+	w.Activate()
+	eventually(t, "focus", w.IsFocused)
+	if f, err := FocusedWindow(); err != nil || !f.same(w) {
+		t.Errorf("FocusedWindow = %v, %v", f, err)
+	}
+
+	if err := w.Maximize(); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "maximized", func() bool { return w.State().Has(Maximized) })
+	if err := w.Fullscreen(); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "fullscreen", func() bool { return w.State().Has(Fullscreen) })
+	w.Minimize()
+	eventually(t, "minimized", w.IsMinimized)
+	if w.IsVisible() {
+		t.Error("minimized window is visible")
+	}
+
+	if err := w.Restore(); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "restored", func() bool {
+		return w.State()&(Minimized|Maximized|Fullscreen) == 0 && w.IsVisible()
+	})
+
+	r := image.Rect(50, 60, 250, 220)
+	if err := w.SetBounds(r); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := w.Bounds(); err != nil || b != r {
+		t.Errorf("Bounds are %v, %v after SetBounds(%v)", b, err, r)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "close", client.Destroyed)
+	eventually(t, "removal from the client list", func() bool {
+		for _, other := range allWindows() {
+			if other.same(w) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func TestRestoreWithoutFullscreenSupport(t *testing.T) {
+	needX(t)
+	hints := []string{}
+	for _, h := range xvfb.EWMH {
+		if h != "_NET_WM_STATE_FULLSCREEN" {
+			hints = append(hints, h)
+		}
+	}
+	startWM(t, hints...)
+	_, w := newTestWindow(t, xvfb.WindowOptions{Title: "no fullscreen"})
+
+	if err := w.Fullscreen(); err != ErrNotSupported {
+		t.Errorf("Fullscreen = %v, want ErrNotSupported", err)
+	}
+	if err := w.Maximize(); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "maximized", func() bool { return w.State().Has(Maximized) })
+	w.Minimize()
+	eventually(t, "minimized", w.IsMinimized)
+
+	if err := w.Restore(); err != nil {
+		t.Fatalf("Restore = %v, want nil", err)
+	}
+	eventually(t, "restored", func() bool { return w.State()&(Minimized|Maximized) == 0 })
+}
+
+func TestClientBounds(t *testing.T) {
+	needX(t)
+	wm := startWM(t, xvfb.EWMH...)
+	client, w := newTestWindow(t, xvfb.WindowOptions{Title: "framed", Bounds: image.Rect(100, 100, 300, 250)})
+	if frame, err := w.ClientBounds(); err != nil || frame != image.Rect(100, 100, 300, 250) {
+		t.Errorf("ClientBounds without extents = %v, %v", frame, err)
+	}
+
+	wm.SetFrameExtents(client.ID, 2, 3, 20, 4)
+	frame, err := w.Bounds()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := image.Rect(98, 80, 303, 254); frame != want {
+		t.Errorf("Bounds = %v, want %v", frame, want)
+	}
+	got, err := w.ClientBounds()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := image.Rect(frame.Min.X+2, frame.Min.Y+20, frame.Max.X-3, frame.Max.Y-4); got != want {
+		t.Errorf("ClientBounds = %v, want %v", got, want)
+	}
+	if got, err := w.Client().Bounds(); err != nil || got != image.Rect(100, 100, 300, 250) {
+		t.Errorf("Client().Bounds() = %v, %v", got, err)
+	}
+}
+
+func TestWaitForWindow(t *testing.T) {
+	needX(t)
+	startWM(t, xvfb.EWMH...)
+	created := make(chan *xvfb.Window, 1)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		client, _ := xvfb.NewWindow(xvfb.WindowOptions{Bounds: image.Rect(0, 0, 100, 100), Title: "late dialog", Type: "_NET_WM_WINDOW_TYPE_DIALOG"})
+		created <- client
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	w, err := WaitForWindow(ctx, Match{TitleRegex: "^late", Role: DialogWindow})
+	if client := <-created; client != nil {
+		defer client.Close()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Title() != "late dialog" {
+		t.Errorf("found %q", w.Title())
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	_, err = WaitForWindow(ctx, Match{TitleRegex: "^missing$"})
+	var timeout *WindowTimeoutError
+	if !errors.As(err, &timeout) || len(timeout.Windows) == 0 {
+		t.Errorf("got %v, want a WindowTimeoutError listing the existing window", err)
+	}
+}
+
+func TestWaitForWindowWakesOnEvent(t *testing.T) {
+	needX(t)
+	startWM(t, xvfb.EWMH...)
+	mapped := make(chan time.Time, 1)
+	go func() {
+		time.Sleep(pollInterval + 50*time.Millisecond)
+		client, err := xvfb.NewWindow(xvfb.WindowOptions{Bounds: image.Rect(0, 0, 100, 100), Title: "prompt"})
+		if err == nil {
+			t.Cleanup(client.Close)
+		}
+		mapped <- time.Now()
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if _, err := WaitForWindow(ctx, Match{TitleRegex: "^prompt$"}); err != nil {
+		t.Fatal(err)
+	}
+	// Polling would take up to pollInterval after the window appeared.
+	if late := time.Since(<-mapped); late > pollInterval/2 {
+		t.Errorf("WaitForWindow returned %v after the window was mapped", late)
+	}
+}
+
+func TestCaptureCoveredWindow(t *testing.T) {
+	needX(t)
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	_, w := newTestWindow(t, xvfb.WindowOptions{Title: "red", Bounds: image.Rect(100, 100, 200, 180), Color: red})
+	// A window on top must not show in the capture.
+	newTestWindow(t, xvfb.WindowOptions{Title: "cover", Bounds: image.Rect(150, 120, 250, 220), Color: color.RGBA{0, 0, 0xff, 0xff}})
+
+	img, err := w.Capture()
+	if err == ErrNotSupported {
+		t.Skip("Composite extension is not available")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds() != image.Rect(100, 100, 200, 180) {
+		t.Fatalf("bounds are %v", img.Bounds())
+	}
+	for _, p := range []image.Point{{100, 100}, {160, 130}, {199, 179}} {
+		if got := img.RGBAAt(p.X, p.Y); got != red {
+			t.Errorf("pixel at %v is %v, want %v", p, got, red)
+		}
+	}
+}
+
+func TestQueryHasWindows(t *testing.T) {
+	needX(t)
+	newTestWindow(t, xvfb.WindowOptions{Title: "query", PID: os.Getpid()})
+	apps, err := Query(Filter{HasWindows: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(apps) != 1 || apps[0].PID() != PID(os.Getpid()) {
+		t.Errorf("apps with windows are %v, want only this process", pids(apps))
+	}
+}
@@ -2,6 +2,7 @@ package app
 
 import (
 	"github.com/kbinani/win"
+	"image"
 	"unsafe"
 	// "fmt"
 )
@@ -35,3 +36,146 @@ func (w *Window) placement() win.WINDOWPLACEMENT {
 	win.GetWindowPlacement(w.hWnd, &p)
 	return p
 }
+
+func (w *Window) minimize() {
+	win.ShowWindow(w.hWnd, win.SW_MINIMIZE)
+}
+
+func (w *Window) title() string {
+	n, _, _ := procGetWindowTextLength.Call(uintptr(w.hWnd))
+	buf := make([]uint16, n+1)
+	procGetWindowText.Call(uintptr(w.hWnd), uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)))
+	return stringFromUnicode16(buf)
+}
+
+func (w *Window) class() string {
+	buf := make([]uint16, 256)
+	procGetClassName.Call(uintptr(w.hWnd), uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)))
+	return stringFromUnicode16(buf)
+}
+
+func (w *Window) windowType() WindowType {
+	exStyle := w.long(gwlExStyle)
+	switch {
+	case exStyle&wsExToolWindow != 0:
+		return UtilityWindow
+	case win.GetWindow(w.hWnd, win.GW_OWNER) != win.HWND(0):
+		// Owned windows are dialogs, such as message boxes.
+		return DialogWindow
+	}
+	return NormalWindow
+}
+
+func (w *Window) state() WindowState {
+	var s WindowState
+	if ret, _, _ := procIsIconic.Call(uintptr(w.hWnd)); ret != 0 {
+		s |= Minimized
+	}
+	if ret, _, _ := procIsZoomed.Call(uintptr(w.hWnd)); ret != 0 {
+		s |= Maximized
+	}
+	if w.long(gwlExStyle)&wsExTopmost != 0 {
+		s |= Above
+	}
+	if w.long(gwlExStyle)&wsExToolWindow != 0 {
+		s |= SkipTaskbar
+	}
+	// A modal window disables its owner until it is closed.
+	if owner := win.GetWindow(w.hWnd, win.GW_OWNER); owner != win.HWND(0) {
+		if ret, _, _ := procIsWindowEnabled.Call(uintptr(owner)); ret == 0 {
+			s |= Modal
+		}
+	}
+	return s
+}
+
+// long returns GetWindowLong of index as unsigned, for testing style bits.
+func (w *Window) long(index int32) uint32 {
+	ret, _, _ := procGetWindowLong.Call(uintptr(w.hWnd), uintptr(index))
+	return uint32(ret)
+}
+
+func (w *Window) bounds() (image.Rectangle, error) {
+	var r rect
+	if ret, _, err := procGetWindowRect.Call(uintptr(w.hWnd), uintptr(unsafe.Pointer(&r))); ret == 0 {
+		return image.Rectangle{}, err
+	}
+	return image.Rect(int(r.Left), int(r.Top), int(r.Right), int(r.Bottom)), nil
+}
+
+func (w *Window) clientBounds() (image.Rectangle, error) {
+	var r rect
+	if ret, _, err := procGetClientRect.Call(uintptr(w.hWnd), uintptr(unsafe.Pointer(&r))); ret == 0 {
+		return image.Rectangle{}, err
+	}
+	var origin point
+	if ret, _, err := procClientToScreen.Call(uintptr(w.hWnd), uintptr(unsafe.Pointer(&origin))); ret == 0 {
+		return image.Rectangle{}, err
+	}
+	return image.Rect(0, 0, int(r.Right), int(r.Bottom)).Add(image.Pt(int(origin.X), int(origin.Y))), nil
+}
+
+func (w *Window) setBounds(r image.Rectangle) error {
+	if w.isMinimized() || w.state().Has(Maximized) {
+		win.ShowWindow(w.hWnd, win.SW_RESTORE)
+	}
+	ret, _, err := procSetWindowPos.Call(uintptr(w.hWnd), 0,
+		uintptr(r.Min.X), uintptr(r.Min.Y), uintptr(r.Dx()), uintptr(r.Dy()),
+		swpNoZOrder|swpNoActivate)
+	if ret == 0 {
+		return err
+	}
+	return nil
+}
+
+func (w *Window) maximize() error {
+	win.ShowWindow(w.hWnd, swMaximize)
+	return nil
+}
+
+func (w *Window) restore() error {
+	win.ShowWindow(w.hWnd, win.SW_RESTORE)
+	return nil
+}
+
+// fullscreen is not supported, since it is up to each app how it goes fullscreen on Windows.
+func (w *Window) fullscreen() error {
+	return ErrNotSupported
+}
+
+func (w *Window) close() error {
+	win.PostMessage(w.hWnd, wmClose, 0, 0)
+	return nil
+}
+
+func (w *Window) isVisible() bool {
+	return win.IsWindowVisible(w.hWnd) && !w.isMinimized()
+}
+
+func (w *Window) isFocused() bool {
+	ret, _, _ := procGetForegroundWindow.Call()
+	return win.HWND(ret) == w.hWnd
+}
+
+func (w *Window) setAlwaysOnTop(on bool) error {
+	after := hwndNoTopmost
+	if on {
+		after = hwndTopmost
+	}
+	ret, _, err := procSetWindowPos.Call(uintptr(w.hWnd), after, 0, 0, 0, 0, swpNoMove|swpNoSize|swpNoActivate)
+	if ret == 0 {
+		return err
+	}
+	return nil
+}
+
+// same reports whether w and other are the same window.
+func (w *Window) same(other *Window) bool {
+	return w.hWnd == other.hWnd
+}
+
+func (w *Window) pid() PID {
+	var pid uint32
+	win.GetWindowThreadProcessId(w.hWnd, &pid)
+	return PID(pid)
+}
@@ -0,0 +1,192 @@
+package app
+
+/*
+#cgo LDFLAGS: -lX11
+#include <stdlib.h>
+#include <X11/Xlib.h>
+#include <X11/Xatom.h>
+
+// item_at returns the i-th item of property data. Items of format 32 are stored as long.
+static unsigned long item_at(unsigned char *data, int format, int i) {
+	switch (format) {
+	case 32:
+		return ((unsigned long *)data)[i];
+	case 16:
+		return ((unsigned short *)data)[i];
+	}
+	return data[i];
+}
+
+// send_client_message sends an EWMH client message about w to the root window, where the window manager receives it.
+static void send_client_message(Display *dpy, Window w, Atom type, long d0, long d1, long d2, long d3, long d4) {
+	XEvent ev = {0};
+	ev.xclient.type = ClientMessage;
+	ev.xclient.window = w;
+	ev.xclient.message_type = type;
+	ev.xclient.format = 32;
+	ev.xclient.data.l[0] = d0;
+	ev.xclient.data.l[1] = d1;
+	ev.xclient.data.l[2] = d2;
+	ev.xclient.data.l[3] = d3;
+	ev.xclient.data.l[4] = d4;
+	XSendEvent(dpy, DefaultRootWindow(dpy), False, SubstructureRedirectMask | SubstructureNotifyMask, &ev);
+}
+*/
+import "C"
+
+import (
+	"github.com/kbinani/robot/internal/x11"
+	"unsafe"
+)
+
+var (
+	// xMutex serializes requests sent through the shared connection, and
+	// guards atoms. It is shared with package robot.
+	xMutex = &x11.Mutex
+	atoms  = map[string]C.Atom{}
+)
+
+// openDisplay returns the connection to the X server named by $DISPLAY, shared by the packages.
+func openDisplay() (*C.Display, error) {
+	dpy, err := x11.Open()
+	return (*C.Display)(dpy), err
+}
+
+// takeXError returns the code of the last X protocol error and clears it. Call XSync before, to receive pending errors.
+func takeXError() int {
+	return x11.TakeError()
+}
+
+// clearXErrors drops the errors of requests sent so far. Call it before a
+// request whose error is checked, as other requests may have failed
+// unnoticed. Callers must hold xMutex.
+func clearXErrors(dpy *C.Display) {
+	x11.ClearErrors(unsafe.Pointer(dpy))
+}
+
+// atom returns the atom named name. Callers must hold xMutex.
+func atom(dpy *C.Display, name string) C.Atom {
+	if a, ok := atoms[name]; ok {
+		return a
+	}
+	s := C.CString(name)
+	defer C.free(unsafe.Pointer(s))
+	a := C.XInternAtom(dpy, s, C.False)
+	atoms[name] = a
+	return a
+}
+
+// atomName returns the name of a. Callers must hold xMutex.
+func atomName(dpy *C.Display, a C.Atom) string {
+	s := C.XGetAtomName(dpy, a)
+	if s == nil {
+		takeXError()
+		return ""
+	}
+	defer C.XFree(unsafe.Pointer(s))
+	return C.GoString(s)
+}
+
+func rootWindow(dpy *C.Display) C.Window {
+	return C.XDefaultRootWindow(dpy)
+}
+
+// property returns the items of property name of w, which must have type typ.
+// Items of format 8 are bytes. Callers must hold xMutex.
+func property(dpy *C.Display, w C.Window, name string, typ C.Atom) ([]uint64, bool) {
+	var actualType C.Atom
+	var format C.int
+	var count, remaining C.ulong
+	var data *C.uchar
+	ret := C.XGetWindowProperty(dpy, w, atom(dpy, name), 0, 1<<24, C.False, typ,
+		&actualType, &format, &count, &remaining, &data)
+	if ret != C.Success || data == nil {
+		// BadWindow, for a window destroyed meanwhile, must not fail later requests.
+		takeXError()
+		return nil, false
+	}
+	defer C.XFree(unsafe.Pointer(data))
+	if actualType != typ {
+		return nil, false
+	}
+	items := make([]uint64, int(count))
+	for i := range items {
+		items[i] = uint64(C.item_at(data, format, C.int(i)))
+	}
+	return items, true
+}
+
+// windowsProperty returns a property of type WINDOW. Callers must hold xMutex.
+func windowsProperty(dpy *C.Display, w C.Window, name string) []C.Window {
+	items, _ := property(dpy, w, name, C.XA_WINDOW)
+	result := make([]C.Window, len(items))
+	for i, item := range items {
+		result[i] = C.Window(item)
+	}
+	return result
+}
+
+// cardinalProperty returns the first item of a property of type CARDINAL. Callers must hold xMutex.
+func cardinalProperty(dpy *C.Display, w C.Window, name string) (uint64, bool) {
+	items, ok := property(dpy, w, name, C.XA_CARDINAL)
+	if !ok || len(items) == 0 {
+		return 0, false
+	}
+	return items[0], true
+}
+
+// atomsProperty returns the names of atoms in a property of type ATOM. Callers must hold xMutex.
+func atomsProperty(dpy *C.Display, w C.Window, name string) []string {
+	items, _ := property(dpy, w, name, C.XA_ATOM)
+	result := make([]string, len(items))
+	for i, item := range items {
+		result[i] = atomName(dpy, C.Atom(item))
+	}
+	return result
+}
+
+// stringProperty returns a text property of type UTF8_STRING, or STRING in
+// Latin-1. Strings in a list are separated by NUL. Callers must hold xMutex.
+func stringProperty(dpy *C.Display, w C.Window, name string) (string, bool) {
+	if items, ok := property(dpy, w, name, atom(dpy, "UTF8_STRING")); ok {
+		b := make([]byte, len(items))
+		for i, item := range items {
+			b[i] = byte(item)
+		}
+		return string(b), true
+	}
+	items, ok := property(dpy, w, name, C.XA_STRING)
+	if !ok {
+		return "", false
+	}
+	r := make([]rune, len(items))
+	for i, item := range items {
+		r[i] = rune(item)
+	}
+	return string(r), true
+}
+
+// sendClientMessage sends a client message of type name about w to the window manager. Callers must hold xMutex.
+func sendClientMessage(dpy *C.Display, w C.Window, name string, data ...int64) {
+	d := make([]C.long, 5)
+	for i, v := range data {
+		d[i] = C.long(v)
+	}
+	C.send_client_message(dpy, w, atom(dpy, name), d[0], d[1], d[2], d[3], d[4])
+	// Window managers ignore messages about unknown windows, so errors are dropped like theirs.
+	C.XSync(dpy, C.False)
+	takeXError()
+}
+
+// supported reports whether the window manager lists name in _NET_SUPPORTED. Callers must hold xMutex.
+func supported(dpy *C.Display, name string) bool {
+	// Atoms are compared rather than their names, which would take a round trip each.
+	want := uint64(atom(dpy, name))
+	items, _ := property(dpy, rootWindow(dpy), "_NET_SUPPORTED", C.XA_ATOM)
+	for _, item := range items {
+		if item == want {
+			return true
+		}
+	}
+	return false
+}
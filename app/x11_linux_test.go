@@ -0,0 +1,80 @@
+package app
+
+import (
+	"fmt"
+	"github.com/kbinani/robot/internal/xvfb"
+	"image"
+	"os"
+	"testing"
+	"time"
+)
+
+// xvfbErr is why tests needing an X server are skipped.
+var xvfbErr error
+
+func TestMain(m *testing.M) {
+	server, err := xvfb.Start(1280, 800, "-br")
+	if err == nil {
+		os.Setenv("DISPLAY", server.Display)
+	} else {
+		xvfbErr = fmt.Errorf("cannot start Xvfb: %v", err)
+	}
+	code := m.Run()
+	if server != nil {
+		server.Stop()
+	}
+	os.Exit(code)
+}
+
+// needX skips the test unless TestMain has started Xvfb.
+func needX(t *testing.T) {
+	if xvfbErr != nil {
+		t.Skip(xvfbErr)
+	}
+}
+
+// startWM runs a window manager supporting hints until the test ends, and returns it.
+func startWM(t *testing.T, hints ...string) *xvfb.WM {
+	wm, err := xvfb.StartWM(hints...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(wm.Stop)
+	return wm
+}
+
+// newTestWindow creates a client window titled opt.Title until the test
+// ends, and returns it with its Window, found by the title.
+func newTestWindow(t *testing.T, opt xvfb.WindowOptions) (*xvfb.Window, *Window) {
+	if opt.Bounds.Empty() {
+		opt.Bounds = image.Rect(100, 100, 300, 250)
+	}
+	client, err := xvfb.NewWindow(opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+	var found *Window
+	eventually(t, "window "+opt.Title, func() bool {
+		for _, w := range allWindows() {
+			if w.Title() == opt.Title {
+				found = w
+				return true
+			}
+		}
+		return false
+	})
+	return client, found
+}
+
+// eventually fails the test unless cond becomes true within a second, as window managers work asynchronously.
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
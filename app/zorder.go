@@ -0,0 +1,77 @@
+package app
+
+import (
+	"fmt"
+	"image"
+)
+
+// WindowsInZOrder returns the top-level windows in stacking order, topmost
+// first. Windows on other virtual desktops or Spaces may be left out.
+func WindowsInZOrder() []*Window {
+	return windowsInZOrder()
+}
+
+// WindowAt returns the topmost visible window whose bounds contain p, in screen coordinates.
+func WindowAt(p image.Point) (*Window, error) {
+	stack := windowsInZOrder()
+	i := topmostAt(visibleBounds(stack), p)
+	if i < 0 {
+		return nil, fmt.Errorf("app: no window at %v", p)
+	}
+	return stack[i], nil
+}
+
+// IsObscured reports whether a visible window stacked above w overlaps r,
+// given in screen coordinates. An empty r means the whole bounds of w. Only
+// bounds are compared, so shaped or translucent windows count as covering.
+func (w *Window) IsObscured(r image.Rectangle) (bool, error) {
+	if r.Empty() {
+		bounds, err := w.Bounds()
+		if err != nil {
+			return false, err
+		}
+		r = bounds
+	}
+	stack := windowsInZOrder()
+	for i, other := range stack {
+		if other.same(w) {
+			return covered(visibleBounds(stack[:i]), r), nil
+		}
+	}
+	return false, fmt.Errorf("app: window %q is not in the stacking order", w.Title())
+}
+
+// visibleBounds returns the bounds of windows, with empty rectangles for
+// those hidden or gone, which cover nothing.
+func visibleBounds(windows []*Window) []image.Rectangle {
+	ret := make([]image.Rectangle, len(windows))
+	for i, w := range windows {
+		if !w.IsVisible() {
+			continue
+		}
+		if bounds, err := w.Bounds(); err == nil {
+			ret[i] = bounds
+		}
+	}
+	return ret
+}
+
+// topmostAt returns the index of the first of stack, ordered topmost first, containing p, or -1.
+func topmostAt(stack []image.Rectangle, p image.Point) int {
+	for i, r := range stack {
+		if p.In(r) {
+			return i
+		}
+	}
+	return -1
+}
+
+// covered reports whether any of stack overlaps r.
+func covered(stack []image.Rectangle, r image.Rectangle) bool {
+	for _, s := range stack {
+		if s.Overlaps(r) {
+			return true
+		}
+	}
+	return false
+}
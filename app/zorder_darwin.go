@@ -0,0 +1,65 @@
+package app
+
+/*
+#cgo LDFLAGS: -framework CoreGraphics -framework CoreFoundation
+#include <stdlib.h>
+#include <CoreGraphics/CoreGraphics.h>
+
+// window_numbers returns the ids of the windows on screen, frontmost first. The result must be freed.
+static CGWindowID *window_numbers(int *count) {
+	*count = 0;
+	CFArrayRef list = CGWindowListCopyWindowInfo(kCGWindowListOptionOnScreenOnly | kCGWindowListExcludeDesktopElements, kCGNullWindowID);
+	if (list == NULL) {
+		return NULL;
+	}
+	CFIndex n = CFArrayGetCount(list);
+	CGWindowID *ids = malloc(sizeof(CGWindowID) * (n > 0 ? n : 1));
+	for (CFIndex i = 0; i < n; i++) {
+		CFDictionaryRef info = CFArrayGetValueAtIndex(list, i);
+		CFNumberRef number = CFDictionaryGetValue(info, kCGWindowNumber);
+		int id;
+		if (number != NULL && CFNumberGetValue(number, kCFNumberIntType, &id)) {
+			ids[(*count)++] = (CGWindowID)id;
+		}
+	}
+	CFRelease(list);
+	return ids;
+}
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+func windowsInZOrder() []*Window {
+	windows := allWindows()
+	byID := map[uint32]*Window{}
+	for _, w := range windows {
+		if id, err := w.axWindow.WindowID(); err == nil {
+			byID[id] = w
+		}
+	}
+	var count C.int
+	ids := C.window_numbers(&count)
+	ret := []*Window{}
+	ordered := map[*Window]bool{}
+	if ids != nil {
+		for _, id := range (*[1 << 28]C.CGWindowID)(unsafe.Pointer(ids))[:count:count] {
+			if w, ok := byID[uint32(id)]; ok {
+				ret = append(ret, w)
+				ordered[w] = true
+			}
+		}
+		C.free(unsafe.Pointer(ids))
+	}
+	// Minimized windows have no place in the order, and go to the bottom.
+	// Other windows missing from the list are on other spaces, and are left
+	// out like cloaked windows on Windows, as isVisible does not tell them.
+	for _, w := range windows {
+		if !ordered[w] && w.axWindow.IsMinimized() {
+			ret = append(ret, w)
+		}
+	}
+	return ret
+}
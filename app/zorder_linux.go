@@ -0,0 +1,25 @@
+package app
+
+// #include <X11/Xlib.h>
+import "C"
+
+func windowsInZOrder() []*Window {
+	dpy, err := openDisplay()
+	if err != nil {
+		return []*Window{}
+	}
+	xMutex.Lock()
+	defer xMutex.Unlock()
+	var ids []C.Window
+	if supported(dpy, "_NET_CLIENT_LIST_STACKING") {
+		ids = windowsProperty(dpy, rootWindow(dpy), "_NET_CLIENT_LIST_STACKING")
+	} else {
+		ids = mappedChildren(dpy, rootWindow(dpy))
+	}
+	// Both lists are bottom-to-top.
+	ret := make([]*Window, 0, len(ids))
+	for i := len(ids) - 1; i >= 0; i-- {
+		ret = append(ret, newWindow(ids[i]))
+	}
+	return ret
+}
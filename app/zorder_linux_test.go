@@ -0,0 +1,45 @@
+package app
+
+import (
+	"github.com/kbinani/robot/internal/xvfb"
+	"image"
+	"testing"
+)
+
+func TestZOrder(t *testing.T) {
+	needX(t)
+	// Windows mapped later are stacked above.
+	_, bottom := newTestWindow(t, xvfb.WindowOptions{Title: "bottom", Bounds: image.Rect(100, 100, 300, 300)})
+	_, top := newTestWindow(t, xvfb.WindowOptions{Title: "top", Bounds: image.Rect(200, 200, 400, 400)})
+
+	stack := WindowsInZOrder()
+	if len(stack) < 2 || !stack[0].same(top) || !stack[1].same(bottom) {
+		t.Fatalf("stacking order is %v, want top, bottom", stack)
+	}
+	tests := []struct {
+		p    image.Point
+		want *Window
+	}{
+		{image.Pt(150, 150), bottom},
+		{image.Pt(250, 250), top},
+		{image.Pt(350, 350), top},
+	}
+	for _, tt := range tests {
+		if w, err := WindowAt(tt.p); err != nil || !w.same(tt.want) {
+			t.Errorf("WindowAt(%v) = %v, %v, want %q", tt.p, w, err, tt.want.Title())
+		}
+	}
+	if w, err := WindowAt(image.Pt(50, 50)); err == nil {
+		t.Errorf("WindowAt on the root window = %q", w.Title())
+	}
+
+	if obscured, err := bottom.IsObscured(image.Rectangle{}); err != nil || !obscured {
+		t.Errorf("bottom.IsObscured = %v, %v, want true", obscured, err)
+	}
+	if obscured, err := bottom.IsObscured(image.Rect(100, 100, 200, 200)); err != nil || obscured {
+		t.Errorf("uncovered part of bottom.IsObscured = %v, %v, want false", obscured, err)
+	}
+	if obscured, err := top.IsObscured(image.Rectangle{}); err != nil || obscured {
+		t.Errorf("top.IsObscured = %v, %v, want false", obscured, err)
+	}
+}
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"image"
+	"testing"
+)
+
+// fakeStack holds the bounds of fake windows, topmost first. The empty one stands for a hidden window.
+var fakeStack = []image.Rectangle{
+	image.Rect(100, 100, 200, 200),
+	{},
+	image.Rect(150, 150, 300, 300),
+	image.Rect(0, 0, 1000, 1000),
+}
+
+func TestTopmostAt(t *testing.T) {
+	tests := []struct {
+		p    image.Point
+		want int
+	}{
+		{image.Pt(120, 120), 0},
+		{image.Pt(160, 160), 0},
+		{image.Pt(250, 250), 2},
+		// Max is outside of a rectangle.
+		{image.Pt(200, 200), 2},
+		{image.Pt(300, 300), 3},
+		{image.Pt(0, 0), 3},
+		{image.Pt(1000, 0), -1},
+		{image.Pt(-1, 50), -1},
+	}
+	for _, tt := range tests {
+		if got := topmostAt(fakeStack, tt.p); got != tt.want {
+			t.Errorf("topmostAt(%v) = %d, want %d", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestCovered(t *testing.T) {
+	tests := []struct {
+		below int
+		r     image.Rectangle
+		want  bool
+	}{
+		// Nothing is above the topmost window.
+		{0, image.Rect(100, 100, 200, 200), false},
+		{2, image.Rect(150, 150, 300, 300), true},
+		{2, image.Rect(200, 150, 300, 300), false},
+		// Touching edges do not overlap.
+		{2, image.Rect(200, 200, 300, 300), false},
+		{2, image.Rect(199, 199, 300, 300), true},
+		{3, image.Rect(250, 250, 260, 260), true},
+		{3, image.Rect(500, 500, 600, 600), false},
+		// A hidden window covers nothing.
+		{2, image.Rect(0, 0, 50, 50), false},
+	}
+	for _, tt := range tests {
+		if got := covered(fakeStack[:tt.below], tt.r); got != tt.want {
+			t.Errorf("covered(%v, %v) = %v, want %v", fakeStack[:tt.below], tt.r, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package app
+
+import (
+	"unsafe"
+)
+
+func windowsInZOrder() []*Window {
+	// EnumWindows already lists top-level windows topmost first. Cloaked
+	// windows, such as suspended UWP apps or those on other virtual desktops,
+	// are visible to IsWindowVisible but not on screen.
+	ret := []*Window{}
+	for _, w := range allWindows() {
+		var cloaked uint32
+		hr, _, _ := procDwmGetWindowAttribute.Call(uintptr(w.hWnd), dwmwaCloaked, uintptr(unsafe.Pointer(&cloaked)), unsafe.Sizeof(cloaked))
+		if hr == 0 && cloaked != 0 {
+			continue
+		}
+		ret = append(ret, w)
+	}
+	return ret
+}
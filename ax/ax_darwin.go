@@ -1,8 +1,13 @@
 // Package ax is a wrapper of accessibility API.
 package ax
 
-// #cgo LDFLAGS: -framework ApplicationServices
-// #include <ApplicationServices/ApplicationServices.h>
+/*
+#cgo LDFLAGS: -framework ApplicationServices
+#include <ApplicationServices/ApplicationServices.h>
+
+// _AXUIElementGetWindow is a private function returning the window server id of a window element.
+extern AXError _AXUIElementGetWindow(AXUIElementRef element, CGWindowID *id);
+*/
 import "C"
 
 import (
@@ -352,6 +357,38 @@ func (ref *UIElement) Perform(action string) {
 	C.AXUIElementPerformAction(ref.obj, a)
 }
 
+// PID is a wrapper func for AXUIElementGetPid. It returns 0 on failure.
+func (ref *UIElement) PID() int {
+	if ref == nil || ref.obj == nil {
+		return 0
+	}
+	var pid C.pid_t
+	if C.AXUIElementGetPid(ref.obj, &pid) != C.kAXErrorSuccess {
+		return 0
+	}
+	return int(pid)
+}
+
+// WindowID returns the id of a window element in the window server, as used by CGWindowList functions.
+func (ref *UIElement) WindowID() (uint32, error) {
+	if ref == nil || ref.obj == nil {
+		return 0, errors.New("nil UIElement")
+	}
+	var id C.CGWindowID
+	if e := C._AXUIElementGetWindow(ref.obj, &id); e != C.kAXErrorSuccess {
+		return 0, fmt.Errorf("_AXUIElementGetWindow returns %d", int(e))
+	}
+	return uint32(id), nil
+}
+
+// Equal reports whether ref and other refer to the same accessibility object.
+func (ref *UIElement) Equal(other *UIElement) bool {
+	if ref == nil || other == nil || ref.obj == nil || other.obj == nil {
+		return false
+	}
+	return C.CFEqual(C.CFTypeRef(ref.obj), C.CFTypeRef(other.obj)) != 0
+}
+
 func convertCFType(obj C.CFTypeRef) interface{} {
 	if obj == nil {
 		return nil
@@ -708,6 +745,38 @@ func (ref *UIElement) URLAttr(attribute string) (*url.URL, error) {
 	return t, nil
 }
 
+// setAttr is a wrapper func for AXUIElementSetAttributeValue.
+func (ref *UIElement) setAttr(attribute string, value C.CFTypeRef) error {
+	a := cfstr(attribute)
+	defer C.CFRelease(C.CFTypeRef(a))
+	if e := C.AXUIElementSetAttributeValue(ref.obj, a, value); e != C.kAXErrorSuccess {
+		return fmt.Errorf("AXUIElementSetAttributeValue returns %d", int(e))
+	}
+	return nil
+}
+
+func (ref *UIElement) SetBoolAttr(attribute string, v bool) error {
+	value := C.kCFBooleanFalse
+	if v {
+		value = C.kCFBooleanTrue
+	}
+	return ref.setAttr(attribute, C.CFTypeRef(value))
+}
+
+func (ref *UIElement) SetPointAttr(attribute string, p Point) error {
+	cgPoint := C.CGPointMake(C.CGFloat(p.X), C.CGFloat(p.Y))
+	value := C.AXValueCreate(C.kAXValueTypeCGPoint, unsafe.Pointer(&cgPoint))
+	defer C.CFRelease(C.CFTypeRef(value))
+	return ref.setAttr(attribute, C.CFTypeRef(value))
+}
+
+func (ref *UIElement) SetSizeAttr(attribute string, s Size) error {
+	cgSize := C.CGSizeMake(C.CGFloat(s.Dx), C.CGFloat(s.Dy))
+	value := C.AXValueCreate(C.kAXValueTypeCGSize, unsafe.Pointer(&cgSize))
+	defer C.CFRelease(C.CFTypeRef(value))
+	return ref.setAttr(attribute, C.CFTypeRef(value))
+}
+
 func finalizeUIElement(ref *UIElement) {
 	if ref != nil {
 		ref.CFRelease()
@@ -0,0 +1,101 @@
+package robot
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"sort"
+)
+
+// CaptureOptions controls how the screen is captured.
+type CaptureOptions struct {
+	// Cursor draws the mouse cursor into the captured image.
+	Cursor bool
+}
+
+// Capture returns a screenshot of rect, given in virtual screen coordinates
+// spanning all displays. The bounds of the returned image equal rect, so
+// that pixels can be addressed by screen coordinates. Parts of rect outside
+// of all displays, including gaps between displays of different sizes, are
+// transparent.
+func Capture(rect image.Rectangle) (*image.RGBA, error) {
+	return CaptureWith(rect, CaptureOptions{})
+}
+
+// CaptureWith is like Capture, with options.
+func CaptureWith(rect image.Rectangle, opt CaptureOptions) (*image.RGBA, error) {
+	rect = rect.Canon()
+	if rect.Empty() {
+		return nil, errors.New("robot: capture rectangle is empty")
+	}
+	img, err := capture(rect, opt.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	list, err := displays()
+	if err != nil {
+		return nil, err
+	}
+	clearOutside(img, list)
+	return img, nil
+}
+
+// clearOutside makes the pixels of img outside of all displays transparent.
+// Platforms fill them with opaque black.
+func clearOutside(img *image.RGBA, displays []image.Rectangle) {
+	b := img.Bounds()
+	for _, d := range displays {
+		if b.In(d) {
+			return
+		}
+	}
+	spans := make([][2]int, 0, len(displays))
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		spans = spans[:0]
+		for _, d := range displays {
+			if d.Min.Y <= y && y < d.Max.Y {
+				spans = append(spans, [2]int{d.Min.X, d.Max.X})
+			}
+		}
+		sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+		row := img.Pix[img.PixOffset(b.Min.X, y):][:4*b.Dx()]
+		erase := func(from, to int) {
+			if to > b.Max.X {
+				to = b.Max.X
+			}
+			for i := 4 * (from - b.Min.X); i < 4*(to-b.Min.X); i++ {
+				row[i] = 0
+			}
+		}
+		// x is the left end of the part of the row not yet known to be on a display.
+		x := b.Min.X
+		for _, s := range spans {
+			if s[0] > x {
+				erase(x, s[0])
+			}
+			if s[1] > x {
+				x = s[1]
+			}
+		}
+		if x < b.Max.X {
+			erase(x, b.Max.X)
+		}
+	}
+}
+
+// Displays returns the bounds of displays in virtual screen coordinates. The primary display comes first.
+func Displays() ([]image.Rectangle, error) {
+	return displays()
+}
+
+// CaptureDisplay returns a screenshot of the n-th display returned by Displays.
+func CaptureDisplay(n int) (*image.RGBA, error) {
+	list, err := Displays()
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 || len(list) <= n {
+		return nil, fmt.Errorf("robot: display %d does not exist", n)
+	}
+	return Capture(list[n])
+}
@@ -0,0 +1,67 @@
+package robot
+
+/*
+#cgo LDFLAGS: -framework CoreGraphics -framework CoreFoundation
+#include <CoreGraphics/CoreGraphics.h>
+
+static int capture_rect(double x, double y, double w, double h, void *out, int stride) {
+	CGImageRef img = CGWindowListCreateImage(CGRectMake(x, y, w, h), kCGWindowListOptionOnScreenOnly, kCGNullWindowID, kCGWindowImageDefault);
+	if (img == NULL) {
+		return 0;
+	}
+	CGColorSpaceRef space = CGColorSpaceCreateWithName(kCGColorSpaceSRGB);
+	CGContextRef ctx = CGBitmapContextCreate(out, (size_t)w, (size_t)h, 8, stride, space, kCGImageAlphaPremultipliedLast | kCGBitmapByteOrder32Big);
+	CGColorSpaceRelease(space);
+	if (ctx == NULL) {
+		CGImageRelease(img);
+		return 0;
+	}
+	// Retina displays return more pixels than points; drawing scales them to the requested size.
+	CGContextDrawImage(ctx, CGRectMake(0, 0, w, h), img);
+	CGContextRelease(ctx);
+	CGImageRelease(img);
+	return 1;
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"image"
+	"unsafe"
+)
+
+func capture(rect image.Rectangle, cursor bool) (*image.RGBA, error) {
+	if cursor {
+		return nil, errors.New("capturing the cursor is not supported on macOS")
+	}
+	img := image.NewRGBA(rect)
+	ok := C.capture_rect(C.double(rect.Min.X), C.double(rect.Min.Y), C.double(rect.Dx()), C.double(rect.Dy()), unsafe.Pointer(&img.Pix[0]), C.int(img.Stride))
+	if ok == 0 {
+		return nil, errors.New("CGWindowListCreateImage failed")
+	}
+	return img, nil
+}
+
+func displays() ([]image.Rectangle, error) {
+	var count C.uint32_t
+	if C.CGGetActiveDisplayList(0, nil, &count) != C.kCGErrorSuccess {
+		return nil, errors.New("CGGetActiveDisplayList failed")
+	}
+	ids := make([]C.CGDirectDisplayID, count)
+	if count > 0 && C.CGGetActiveDisplayList(count, &ids[0], &count) != C.kCGErrorSuccess {
+		return nil, errors.New("CGGetActiveDisplayList failed")
+	}
+	main := C.CGMainDisplayID()
+	result := []image.Rectangle{}
+	for _, id := range ids[:count] {
+		b := C.CGDisplayBounds(id)
+		r := image.Rect(int(b.origin.x), int(b.origin.y), int(b.origin.x+b.size.width), int(b.origin.y+b.size.height))
+		if id == main {
+			result = append([]image.Rectangle{r}, result...)
+		} else {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,218 @@
+package robot
+
+/*
+#cgo LDFLAGS: -lX11 -lXext -lXfixes -lXrandr
+#include <stdlib.h>
+#include <string.h>
+#include <sys/ipc.h>
+#include <sys/shm.h>
+#include <X11/Xlib.h>
+#include <X11/Xutil.h>
+#include <X11/extensions/XShm.h>
+#include <X11/extensions/Xfixes.h>
+#include <X11/extensions/Xrandr.h>
+
+typedef struct {
+	XImage *img;
+	XShmSegmentInfo info;
+} shm_image;
+
+static void shm_release(shm_image *s) {
+	s->img->data = NULL;
+	XDestroyImage(s->img);
+	if (s->info.shmaddr != (char *)-1) {
+		shmdt(s->info.shmaddr);
+	}
+	if (s->info.shmid >= 0) {
+		shmctl(s->info.shmid, IPC_RMID, NULL);
+	}
+	free(s);
+}
+
+// shm_get_image grabs the area through shared memory, which avoids copying the pixels through the socket. Release the result with shm_release.
+static shm_image *shm_get_image(Display *dpy, int x, int y, int w, int h) {
+	int screen = DefaultScreen(dpy);
+	shm_image *s = calloc(1, sizeof(shm_image));
+	if (s == NULL) {
+		return NULL;
+	}
+	s->img = XShmCreateImage(dpy, DefaultVisual(dpy, screen), DefaultDepth(dpy, screen), ZPixmap, NULL, &s->info, w, h);
+	if (s->img == NULL) {
+		free(s);
+		return NULL;
+	}
+	s->info.shmaddr = (char *)-1;
+	s->info.shmid = shmget(IPC_PRIVATE, s->img->bytes_per_line * s->img->height, IPC_CREAT | 0600);
+	if (s->info.shmid < 0) {
+		shm_release(s);
+		return NULL;
+	}
+	s->info.shmaddr = s->img->data = shmat(s->info.shmid, NULL, 0);
+	s->info.readOnly = False;
+	int attached = s->info.shmaddr != (char *)-1 && XShmAttach(dpy, &s->info);
+	XSync(dpy, False);
+	int ok = attached && XShmGetImage(dpy, RootWindow(dpy, screen), s->img, x, y, AllPlanes);
+	if (attached) {
+		XShmDetach(dpy, &s->info);
+		XSync(dpy, False);
+	}
+	if (!ok) {
+		shm_release(s);
+		return NULL;
+	}
+	return s;
+}
+
+static XImage *get_image(Display *dpy, int x, int y, int w, int h) {
+	return XGetImage(dpy, DefaultRootWindow(dpy), x, y, w, h, AllPlanes, ZPixmap);
+}
+
+static void destroy_image(XImage *img) {
+	XDestroyImage(img);
+}
+
+static int screen_width(Display *dpy) {
+	return DisplayWidth(dpy, DefaultScreen(dpy));
+}
+
+static int screen_height(Display *dpy) {
+	return DisplayHeight(dpy, DefaultScreen(dpy));
+}
+
+static int has_randr_monitors(Display *dpy) {
+	int event, error, major = 0, minor = 0;
+	if (!XRRQueryExtension(dpy, &event, &error) || !XRRQueryVersion(dpy, &major, &minor)) {
+		return 0;
+	}
+	return major > 1 || (major == 1 && minor >= 5);
+}
+
+static unsigned long cursor_pixel(XFixesCursorImage *img, int i) {
+	return img->pixels[i];
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"github.com/kbinani/robot/internal/x11"
+	"image"
+	"image/draw"
+	"unsafe"
+)
+
+var shmUnavailable bool
+
+func capture(rect image.Rectangle, cursor bool) (*image.RGBA, error) {
+	dpy, err := openDisplay()
+	if err != nil {
+		return nil, err
+	}
+	displayMutex.Lock()
+	defer displayMutex.Unlock()
+
+	img := image.NewRGBA(rect)
+	screen := image.Rect(0, 0, int(C.screen_width(dpy)), int(C.screen_height(dpy)))
+	area := rect.Intersect(screen)
+	if area.Empty() {
+		return img, nil
+	}
+	if err := grab(dpy, area, img); err != nil {
+		return nil, err
+	}
+	if cursor {
+		if c := cursorImage(dpy); c != nil {
+			draw.Draw(img, c.Bounds(), c, c.Bounds().Min, draw.Over)
+		}
+	}
+	return img, nil
+}
+
+// grab copies area of the root window into img.
+func grab(dpy *C.Display, area image.Rectangle, img *image.RGBA) error {
+	x, y := C.int(area.Min.X), C.int(area.Min.Y)
+	w, h := C.int(area.Dx()), C.int(area.Dy())
+
+	clearXErrors(dpy)
+	if !shmUnavailable && C.XShmQueryExtension(dpy) != 0 {
+		s := C.shm_get_image(dpy, x, y, w, h)
+		// Attaching fails on connections to a remote server, which do not share memory.
+		if takeXError() == 0 && s != nil {
+			x11.ConvertImage(unsafe.Pointer(s.img), img, area.Min)
+			C.shm_release(s)
+			return nil
+		}
+		if s != nil {
+			C.shm_release(s)
+		}
+		shmUnavailable = true
+	}
+	ximg := C.get_image(dpy, x, y, w, h)
+	C.XSync(dpy, C.False)
+	if takeXError() != 0 || ximg == nil {
+		if ximg != nil {
+			C.destroy_image(ximg)
+		}
+		return errors.New("XGetImage failed")
+	}
+	x11.ConvertImage(unsafe.Pointer(ximg), img, area.Min)
+	C.destroy_image(ximg)
+	return nil
+}
+
+// cursorImage returns the cursor image placed at the cursor position, or nil if XFIXES is not available.
+func cursorImage(dpy *C.Display) *image.RGBA {
+	var event, errorBase C.int
+	if C.XFixesQueryExtension(dpy, &event, &errorBase) == 0 {
+		return nil
+	}
+	ci := C.XFixesGetCursorImage(dpy)
+	if ci == nil {
+		return nil
+	}
+	defer C.XFree(unsafe.Pointer(ci))
+	w, h := int(ci.width), int(ci.height)
+	min := image.Pt(int(ci.x)-int(ci.xhot), int(ci.y)-int(ci.yhot))
+	img := image.NewRGBA(image.Rectangle{min, min.Add(image.Pt(w, h))})
+	for i := 0; i < w*h; i++ {
+		// Pixels are premultiplied ARGB, stored in unsigned long.
+		p := uint32(C.cursor_pixel(ci, C.int(i)))
+		j := 4 * i
+		img.Pix[j+0] = uint8(p >> 16)
+		img.Pix[j+1] = uint8(p >> 8)
+		img.Pix[j+2] = uint8(p)
+		img.Pix[j+3] = uint8(p >> 24)
+	}
+	return img
+}
+
+func displays() ([]image.Rectangle, error) {
+	dpy, err := openDisplay()
+	if err != nil {
+		return nil, err
+	}
+	displayMutex.Lock()
+	defer displayMutex.Unlock()
+
+	root := image.Rect(0, 0, int(C.screen_width(dpy)), int(C.screen_height(dpy)))
+	if C.has_randr_monitors(dpy) == 0 {
+		return []image.Rectangle{root}, nil
+	}
+	var n C.int
+	monitors := C.XRRGetMonitors(dpy, C.XDefaultRootWindow(dpy), C.True, &n)
+	if monitors == nil || n == 0 {
+		return []image.Rectangle{root}, nil
+	}
+	defer C.XRRFreeMonitors(monitors)
+	list := (*[1 << 16]C.XRRMonitorInfo)(unsafe.Pointer(monitors))[:n:n]
+	result := []image.Rectangle{}
+	for _, m := range list {
+		r := image.Rect(int(m.x), int(m.y), int(m.x)+int(m.width), int(m.y)+int(m.height))
+		if m.primary != 0 {
+			result = append([]image.Rectangle{r}, result...)
+		} else {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
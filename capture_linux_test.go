@@ -0,0 +1,131 @@
+package robot
+
+import (
+	"context"
+	"github.com/kbinani/robot/internal/xvfb"
+	"image"
+	"image/color"
+	"testing"
+	"time"
+)
+
+var (
+	red  = color.RGBA{0xff, 0, 0, 0xff}
+	blue = color.RGBA{0, 0, 0xff, 0xff}
+)
+
+func TestCaptureKnownContent(t *testing.T) {
+	needX(t)
+	if err := xvfb.Fill(image.Rect(100, 100, 200, 150), red); err != nil {
+		t.Fatal(err)
+	}
+	if err := xvfb.Fill(image.Rect(200, 100, 300, 150), blue); err != nil {
+		t.Fatal(err)
+	}
+	rect := image.Rect(90, 90, 310, 160)
+	img, err := Capture(rect)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds() != rect {
+		t.Fatalf("bounds are %v, want %v", img.Bounds(), rect)
+	}
+	tests := []struct {
+		p    image.Point
+		want color.RGBA
+	}{
+		{image.Pt(100, 100), red},
+		{image.Pt(199, 149), red},
+		{image.Pt(200, 100), blue},
+		{image.Pt(299, 149), blue},
+		{image.Pt(95, 95), color.RGBA{0, 0, 0, 0xff}},
+		{image.Pt(300, 150), color.RGBA{0, 0, 0, 0xff}},
+	}
+	for _, tt := range tests {
+		if got := img.RGBAAt(tt.p.X, tt.p.Y); got != tt.want {
+			t.Errorf("pixel at %v is %v, want %v", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestCaptureOutsideScreen(t *testing.T) {
+	needX(t)
+	if err := xvfb.Fill(image.Rect(0, 0, 10, 10), red); err != nil {
+		t.Fatal(err)
+	}
+	img, err := Capture(image.Rect(-10, -10, 10, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := img.RGBAAt(5, 5); got != red {
+		t.Errorf("pixel on screen is %v, want %v", got, red)
+	}
+	for _, p := range []image.Point{{-5, -5}, {5, -5}, {-5, 5}} {
+		if got := img.RGBAAt(p.X, p.Y); got.A != 0 {
+			t.Errorf("pixel off screen at %v is %v, want transparent", p, got)
+		}
+	}
+}
+
+func TestDisplaysAndCaptureDisplay(t *testing.T) {
+	needX(t)
+	list, err := Displays()
+	if err != nil {
+		t.Fatal(err)
+	}
+	screen := image.Rect(0, 0, 1280, 800)
+	if len(list) != 1 || list[0] != screen {
+		t.Fatalf("Displays() = %v, want [%v]", list, screen)
+	}
+	img, err := CaptureDisplay(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds() != screen {
+		t.Errorf("bounds are %v, want %v", img.Bounds(), screen)
+	}
+	if _, err := CaptureDisplay(1); err == nil {
+		t.Error("CaptureDisplay(1) succeeds with a single display")
+	}
+}
+
+func TestDisplayFrame(t *testing.T) {
+	needX(t)
+	displays, err := Displays()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := Display(0).Bounds(); err != nil || got != displays[0] {
+		t.Errorf("Display(0).Bounds() = %v, %v, want %v", got, err, displays[0])
+	}
+	for _, n := range []int{-1, len(displays)} {
+		if _, err := Display(n).Bounds(); err == nil {
+			t.Errorf("Display(%d) of %d exists", n, len(displays))
+		}
+	}
+}
+
+func TestCaptureWithCursor(t *testing.T) {
+	needX(t)
+	if err := Mmv(image.Pt(400, 400)); err != nil {
+		t.Fatal(err)
+	}
+	rect := image.Rect(380, 380, 420, 420)
+	if _, err := CaptureWith(rect, CaptureOptions{Cursor: true}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWaitForRegionStableZeroQuietPeriod(t *testing.T) {
+	needX(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	rect := image.Rect(0, 0, 10, 10)
+	img, err := WaitForRegionStable(ctx, rect, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds() != rect {
+		t.Errorf("bounds are %v, want %v", img.Bounds(), rect)
+	}
+}
@@ -0,0 +1,70 @@
+package robot
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+func TestClearOutside(t *testing.T) {
+	// A 1920x1080 primary display with a 1280x720 display to its right, top-aligned.
+	displays := []image.Rectangle{
+		image.Rect(0, 0, 1920, 1080),
+		image.Rect(1920, 0, 3200, 720),
+	}
+	img := image.NewRGBA(image.Rect(1900, 700, 3300, 1100))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.RGBA{0, 0, 0, 0xff}), image.Point{}, draw.Src)
+	clearOutside(img, displays)
+
+	tests := []struct {
+		p      image.Point
+		opaque bool
+	}{
+		{image.Pt(1900, 700), true},
+		{image.Pt(1919, 1079), true},
+		{image.Pt(1920, 719), true},
+		{image.Pt(3199, 719), true},
+		// Below the shorter display.
+		{image.Pt(1920, 720), false},
+		{image.Pt(2500, 1000), false},
+		// Right of the virtual screen.
+		{image.Pt(3200, 700), false},
+		// Below the virtual screen.
+		{image.Pt(1900, 1080), false},
+	}
+	for _, tt := range tests {
+		if a := img.RGBAAt(tt.p.X, tt.p.Y).A; (a == 0xff) != tt.opaque {
+			t.Errorf("alpha at %v is %d, want opaque %v", tt.p, a, tt.opaque)
+		}
+	}
+}
+
+func TestClearOutsideInsideDisplay(t *testing.T) {
+	img := image.NewRGBA(image.Rect(10, 10, 20, 20))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.RGBA{1, 2, 3, 0xff}), image.Point{}, draw.Src)
+	clearOutside(img, []image.Rectangle{image.Rect(0, 0, 100, 100)})
+	for i, v := range img.Pix {
+		if want := []uint8{1, 2, 3, 0xff}[i%4]; v != want {
+			t.Fatalf("byte %d changed to %d", i, v)
+		}
+	}
+}
+
+func TestClearOutsideOverlapping(t *testing.T) {
+	// Mirrored or overlapping displays must not clear what one of them covers.
+	displays := []image.Rectangle{
+		image.Rect(0, 0, 100, 100),
+		image.Rect(50, 0, 120, 100),
+		image.Rect(10, 0, 60, 100),
+	}
+	img := image.NewRGBA(image.Rect(-10, 0, 130, 1))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.RGBA{0, 0, 0, 0xff}), image.Point{}, draw.Src)
+	clearOutside(img, displays)
+	for x := -10; x < 130; x++ {
+		want := 0 <= x && x < 120
+		if a := img.RGBAAt(x, 0).A; (a == 0xff) != want {
+			t.Errorf("alpha at x=%d is %d, want opaque %v", x, a, want)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package robot
+
+import (
+	"errors"
+	"github.com/kbinani/win"
+	"image"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// Functions which github.com/kbinani/win does not provide.
+var (
+	user32                  = syscall.NewLazyDLL("user32.dll")
+	procGetCursorInfo       = user32.NewProc("GetCursorInfo")
+	procGetIconInfo         = user32.NewProc("GetIconInfo")
+	procDrawIconEx          = user32.NewProc("DrawIconEx")
+	procEnumDisplayMonitors = user32.NewProc("EnumDisplayMonitors")
+	procGetMonitorInfo      = user32.NewProc("GetMonitorInfoW")
+)
+
+const (
+	captureblt      = 0x40000000
+	cursorShowing   = 0x00000001
+	diNormal        = 0x0003
+	monitorinfofPri = 0x00000001
+)
+
+type winPoint struct {
+	x, y int32
+}
+
+type winRect struct {
+	left, top, right, bottom int32
+}
+
+type cursorInfo struct {
+	cbSize      uint32
+	flags       uint32
+	hCursor     uintptr
+	ptScreenPos winPoint
+}
+
+type iconInfo struct {
+	fIcon    int32
+	xHotspot uint32
+	yHotspot uint32
+	hbmMask  uintptr
+	hbmColor uintptr
+}
+
+type monitorInfo struct {
+	cbSize    uint32
+	rcMonitor winRect
+	rcWork    winRect
+	dwFlags   uint32
+}
+
+func capture(rect image.Rectangle, cursor bool) (*image.RGBA, error) {
+	screen := win.GetDC(0)
+	if screen == 0 {
+		return nil, errors.New("GetDC failed")
+	}
+	defer win.ReleaseDC(0, screen)
+	mem := win.CreateCompatibleDC(screen)
+	if mem == 0 {
+		return nil, errors.New("CreateCompatibleDC failed")
+	}
+	defer win.DeleteDC(mem)
+	w, h := int32(rect.Dx()), int32(rect.Dy())
+	bitmap := win.CreateCompatibleBitmap(screen, w, h)
+	if bitmap == 0 {
+		return nil, errors.New("CreateCompatibleBitmap failed")
+	}
+	defer win.DeleteObject(win.HGDIOBJ(bitmap))
+	old := win.SelectObject(mem, win.HGDIOBJ(bitmap))
+
+	x, y := int32(rect.Min.X), int32(rect.Min.Y)
+	if !win.BitBlt(mem, 0, 0, w, h, screen, x, y, win.SRCCOPY|captureblt) {
+		win.SelectObject(mem, old)
+		return nil, errors.New("BitBlt failed")
+	}
+	if cursor {
+		drawCursor(uintptr(mem), rect.Min)
+	}
+	win.SelectObject(mem, old)
+
+	var info win.BITMAPINFO
+	info.BmiHeader.BiSize = uint32(unsafe.Sizeof(info.BmiHeader))
+	info.BmiHeader.BiWidth = w
+	info.BmiHeader.BiHeight = -h // top-down
+	info.BmiHeader.BiPlanes = 1
+	info.BmiHeader.BiBitCount = 32
+	info.BmiHeader.BiCompression = win.BI_RGB
+	img := image.NewRGBA(rect)
+	if win.GetDIBits(mem, bitmap, 0, uint32(h), &img.Pix[0], &info, win.DIB_RGB_COLORS) == 0 {
+		return nil, errors.New("GetDIBits failed")
+	}
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+2] = img.Pix[i+2], img.Pix[i]
+		img.Pix[i+3] = 0xff
+	}
+	return img, nil
+}
+
+func drawCursor(dc uintptr, origin image.Point) {
+	var ci cursorInfo
+	ci.cbSize = uint32(unsafe.Sizeof(ci))
+	if ret, _, _ := procGetCursorInfo.Call(uintptr(unsafe.Pointer(&ci))); ret == 0 || ci.flags&cursorShowing == 0 {
+		return
+	}
+	var ii iconInfo
+	if ret, _, _ := procGetIconInfo.Call(ci.hCursor, uintptr(unsafe.Pointer(&ii))); ret == 0 {
+		return
+	}
+	if ii.hbmMask != 0 {
+		defer win.DeleteObject(win.HGDIOBJ(ii.hbmMask))
+	}
+	if ii.hbmColor != 0 {
+		defer win.DeleteObject(win.HGDIOBJ(ii.hbmColor))
+	}
+	x := int(ci.ptScreenPos.x) - int(ii.xHotspot) - origin.X
+	y := int(ci.ptScreenPos.y) - int(ii.yHotspot) - origin.Y
+	procDrawIconEx.Call(dc, uintptr(x), uintptr(y), ci.hCursor, 0, 0, 0, 0, diNormal)
+}
+
+var (
+	// enumMonitorProc is created once, as the runtime allows a limited number of callbacks.
+	enumMonitorProc = syscall.NewCallback(enumMonitor)
+	// monitorsMutex guards monitors, which enumMonitor fills during EnumDisplayMonitors.
+	monitorsMutex sync.Mutex
+	monitors      []image.Rectangle
+)
+
+// enumMonitor appends the bounds of hMonitor to monitors, the primary display first.
+func enumMonitor(hMonitor, hdc, lprc, lParam uintptr) uintptr {
+	var info monitorInfo
+	info.cbSize = uint32(unsafe.Sizeof(info))
+	if ret, _, _ := procGetMonitorInfo.Call(hMonitor, uintptr(unsafe.Pointer(&info))); ret == 0 {
+		return 1
+	}
+	r := info.rcMonitor
+	bounds := image.Rect(int(r.left), int(r.top), int(r.right), int(r.bottom))
+	if info.dwFlags&monitorinfofPri != 0 {
+		monitors = append([]image.Rectangle{bounds}, monitors...)
+	} else {
+		monitors = append(monitors, bounds)
+	}
+	return 1
+}
+
+func displays() ([]image.Rectangle, error) {
+	monitorsMutex.Lock()
+	defer monitorsMutex.Unlock()
+	monitors = []image.Rectangle{}
+	if ret, _, _ := procEnumDisplayMonitors.Call(0, 0, enumMonitorProc, 0); ret == 0 {
+		return nil, errors.New("EnumDisplayMonitors failed")
+	}
+	return monitors, nil
+}
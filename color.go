@@ -0,0 +1,111 @@
+package robot
+
+import (
+	"image"
+	"image/color"
+)
+
+// Pixel returns the color of the screen at p.
+func Pixel(p image.Point) (color.RGBA, error) {
+	img, err := Capture(image.Rectangle{p, p.Add(image.Pt(1, 1))})
+	if err != nil {
+		return color.RGBA{}, err
+	}
+	return img.RGBAAt(p.X, p.Y), nil
+}
+
+// FindColor returns the points in region whose color is within tolerance of
+// c. tolerance is the largest difference allowed in each of the red, green
+// and blue channels, from 0 (exact) to 255. region is captured once. Points
+// outside every display, which are captured as transparent, never match.
+func FindColor(region image.Rectangle, c color.Color, tolerance int) ([]image.Point, error) {
+	img, err := Capture(region)
+	if err != nil {
+		return nil, err
+	}
+	return ColorPoints(img, c, tolerance), nil
+}
+
+// FindColorBoxes is like FindColor, but returns the bounding boxes of connected areas of matching points.
+func FindColorBoxes(region image.Rectangle, c color.Color, tolerance int) ([]image.Rectangle, error) {
+	img, err := Capture(region)
+	if err != nil {
+		return nil, err
+	}
+	return ColorBoxes(img, c, tolerance), nil
+}
+
+// ColorPoints returns the points of img whose color is within tolerance of c, in the same way as FindColor.
+func ColorPoints(img *image.RGBA, c color.Color, tolerance int) []image.Point {
+	points := []image.Point{}
+	b := img.Bounds()
+	mask := colorMask(img, c, tolerance)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if mask[(y-b.Min.Y)*b.Dx()+(x-b.Min.X)] {
+				points = append(points, image.Pt(x, y))
+			}
+		}
+	}
+	return points
+}
+
+// ColorBoxes returns the bounding boxes of 8-connected areas of img whose color is within tolerance of c.
+func ColorBoxes(img *image.RGBA, c color.Color, tolerance int) []image.Rectangle {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	mask := colorMask(img, c, tolerance)
+	boxes := []image.Rectangle{}
+	stack := []int{}
+	for start := range mask {
+		if !mask[start] {
+			continue
+		}
+		mask[start] = false
+		stack = append(stack[:0], start)
+		box := image.Rect(start%w, start/w, start%w+1, start/w+1)
+		for len(stack) > 0 {
+			i := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			x, y := i%w, i/w
+			box = box.Union(image.Rect(x, y, x+1, y+1))
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					nx, ny := x+dx, y+dy
+					if nx < 0 || ny < 0 || nx >= w || ny >= h {
+						continue
+					}
+					if j := ny*w + nx; mask[j] {
+						mask[j] = false
+						stack = append(stack, j)
+					}
+				}
+			}
+		}
+		boxes = append(boxes, box.Add(b.Min))
+	}
+	return boxes
+}
+
+// colorMask returns whether each pixel of img matches c, in row-major order
+// relative to the bounds of img. Transparent pixels do not match.
+func colorMask(img *image.RGBA, c color.Color, tolerance int) []bool {
+	want := color.RGBAModel.Convert(c).(color.RGBA)
+	b := img.Bounds()
+	mask := make([]bool, b.Dx()*b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		row := img.Pix[img.PixOffset(b.Min.X, y):]
+		for x := 0; x < b.Dx(); x++ {
+			p := row[4*x : 4*x+4]
+			mask[(y-b.Min.Y)*b.Dx()+x] = p[3] != 0 &&
+				absDiff(p[0], want.R) <= tolerance &&
+				absDiff(p[1], want.G) <= tolerance &&
+				absDiff(p[2], want.B) <= tolerance
+		}
+	}
+	return mask
+}
+
+func absDiff(a, b uint8) int {
+	return abs(int(a) - int(b))
+}
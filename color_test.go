@@ -0,0 +1,111 @@
+package robot
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+// paint returns an opaque black image with bounds r, and the given points set to c.
+func paint(r image.Rectangle, c color.RGBA, points ...image.Point) *image.RGBA {
+	img := image.NewRGBA(r)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetRGBA(x, y, color.RGBA{0, 0, 0, 0xff})
+		}
+	}
+	for _, p := range points {
+		img.SetRGBA(p.X, p.Y, c)
+	}
+	return img
+}
+
+func TestColorPointsTolerance(t *testing.T) {
+	want := color.RGBA{100, 150, 200, 0xff}
+	tests := []struct {
+		c         color.RGBA
+		tolerance int
+		match     bool
+	}{
+		{color.RGBA{100, 150, 200, 0xff}, 0, true},
+		{color.RGBA{101, 150, 200, 0xff}, 0, false},
+		{color.RGBA{110, 140, 210, 0xff}, 10, true},
+		{color.RGBA{111, 150, 200, 0xff}, 10, false},
+		{color.RGBA{100, 139, 200, 0xff}, 10, false},
+		{color.RGBA{100, 150, 211, 0xff}, 10, false},
+		// Alpha of opaque pixels is not compared.
+		{color.RGBA{100, 150, 200, 0x80}, 0, true},
+	}
+	for _, tt := range tests {
+		img := paint(image.Rect(0, 0, 3, 3), tt.c, image.Pt(1, 1))
+		got := ColorPoints(img, want, tt.tolerance)
+		if matched := reflect.DeepEqual(got, []image.Point{{1, 1}}); matched != tt.match {
+			t.Errorf("ColorPoints of %v with tolerance %d = %v, want match %v", tt.c, tt.tolerance, got, tt.match)
+		}
+	}
+}
+
+func TestColorPointsTransparent(t *testing.T) {
+	// Areas outside every display are captured as transparent black.
+	img := paint(image.Rect(10, 10, 14, 12), color.RGBA{}, image.Pt(10, 10), image.Pt(13, 11))
+	got := ColorPoints(img, color.Black, 0)
+	if len(got) != 6 {
+		t.Errorf("ColorPoints = %v, want the 6 opaque points", got)
+	}
+	for _, p := range got {
+		if p == image.Pt(10, 10) || p == image.Pt(13, 11) {
+			t.Errorf("transparent point %v matched", p)
+		}
+	}
+	if boxes := ColorBoxes(img, color.Black, 255); !reflect.DeepEqual(boxes, []image.Rectangle{image.Rect(10, 10, 14, 12)}) {
+		t.Errorf("ColorBoxes = %v", boxes)
+	}
+	empty := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	if got := ColorBoxes(empty, color.Black, 255); len(got) != 0 {
+		t.Errorf("ColorBoxes of a transparent image = %v", got)
+	}
+}
+
+func TestColorBoxes(t *testing.T) {
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	tests := []struct {
+		name   string
+		points []image.Point
+		want   []image.Rectangle
+	}{
+		{"none", nil, []image.Rectangle{}},
+		{"single", []image.Point{{102, 51}}, []image.Rectangle{image.Rect(102, 51, 103, 52)}},
+		{"row", []image.Point{{101, 51}, {102, 51}, {103, 51}}, []image.Rectangle{image.Rect(101, 51, 104, 52)}},
+		// Diagonal neighbours are connected.
+		{"diagonal", []image.Point{{101, 51}, {102, 52}, {103, 53}}, []image.Rectangle{image.Rect(101, 51, 104, 54)}},
+		{"L shape", []image.Point{{101, 51}, {101, 52}, {101, 53}, {102, 53}, {103, 53}}, []image.Rectangle{image.Rect(101, 51, 104, 54)}},
+		// A gap of one pixel separates areas. Boxes are ordered by their first point in rows.
+		{
+			"separate",
+			[]image.Point{{104, 56}, {101, 51}, {103, 51}},
+			[]image.Rectangle{image.Rect(101, 51, 102, 52), image.Rect(103, 51, 104, 52), image.Rect(104, 56, 105, 57)},
+		},
+		// Areas reach the edges of the image.
+		{"corners", []image.Point{{100, 50}, {109, 59}}, []image.Rectangle{image.Rect(100, 50, 101, 51), image.Rect(109, 59, 110, 60)}},
+	}
+	for _, tt := range tests {
+		img := paint(image.Rect(100, 50, 110, 60), red, tt.points...)
+		if got := ColorBoxes(img, red, 0); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: ColorBoxes = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestColorBoxesTolerance(t *testing.T) {
+	img := paint(image.Rect(0, 0, 6, 1), color.RGBA{}, image.Pt(5, 0))
+	img.SetRGBA(0, 0, color.RGBA{200, 200, 200, 0xff})
+	img.SetRGBA(1, 0, color.RGBA{210, 200, 200, 0xff})
+	img.SetRGBA(2, 0, color.RGBA{211, 200, 200, 0xff})
+	img.SetRGBA(3, 0, color.RGBA{190, 190, 190, 0xff})
+	got := ColorBoxes(img, color.RGBA{200, 200, 200, 0xff}, 10)
+	want := []image.Rectangle{image.Rect(0, 0, 2, 1), image.Rect(3, 0, 4, 1)}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ColorBoxes = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,237 @@
+package robot
+
+import (
+	"context"
+	"errors"
+	"image"
+	"sync"
+	"time"
+)
+
+// ErrUserInterrupted is returned while a Guard in GuardAbort mode has detected input by the physical user.
+var ErrUserInterrupted = errors.New("robot: interrupted by user")
+
+// GuardMode represents what a Guard does when the physical user intervenes.
+type GuardMode int
+
+// Guard modes.
+const (
+	// GuardAbort makes Mmv, Btn, Kbd, Type and Guard.Check return
+	// ErrUserInterrupted without injecting input, until Guard.Reset is called.
+	// Releases of buttons and keys are still injected so nothing is left pressed.
+	GuardAbort GuardMode = iota
+	// GuardPause blocks Mmv, Btn, Kbd and Type until the user has been quiet for
+	// the QuietPeriod. They fail with the error of the context given to
+	// EnableGuard if it is done meanwhile.
+	GuardPause
+)
+
+// GuardOptions configures a Guard.
+type GuardOptions struct {
+	Mode GuardMode
+	// QuietPeriod is how long the user must stay away before a paused sequence resumes. Default is 2s.
+	QuietPeriod time.Duration
+	// Tolerance is the distance in pixels the cursor may drift from the last Mmv or Btn position. Default is 2.
+	Tolerance int
+	// Interval is the polling interval of the guard. Default is 50ms.
+	Interval time.Duration
+	// Slack is the time after an injected input during which input is attributed to this process. Default is 200ms.
+	Slack time.Duration
+}
+
+// Guard watches for pointer or keyboard activity not injected by this
+// package, and for the cursor leaving the position set by the last Mmv or
+// Btn. Input injected by other processes is also treated as user activity.
+type Guard struct {
+	opt  GuardOptions
+	ctx  context.Context
+	done chan struct{}
+
+	mutex        sync.Mutex
+	lastActivity time.Time
+	tripped      bool
+}
+
+var (
+	injectMutex  sync.Mutex
+	lastInjected time.Time
+	lastTarget   image.Point
+	hasTarget    bool
+	activeGuard  *Guard
+)
+
+// EnableGuard starts watching for user intervention. Only one guard can be
+// enabled at a time. When ctx is done, the guard is disabled, and input paused
+// by it fails with ctx.Err().
+func EnableGuard(ctx context.Context, opt GuardOptions) (*Guard, error) {
+	if opt.QuietPeriod <= 0 {
+		opt.QuietPeriod = 2 * time.Second
+	}
+	if opt.Tolerance <= 0 {
+		opt.Tolerance = 2
+	}
+	if opt.Interval <= 0 {
+		opt.Interval = 50 * time.Millisecond
+	}
+	if opt.Slack <= 0 {
+		opt.Slack = 200 * time.Millisecond
+	}
+	if _, err := IdleTime(); err != nil {
+		return nil, err
+	}
+	g := &Guard{opt: opt, ctx: ctx, done: make(chan struct{})}
+
+	injectMutex.Lock()
+	defer injectMutex.Unlock()
+	if activeGuard != nil {
+		return nil, errors.New("robot: guard is already enabled")
+	}
+	activeGuard = g
+	lastInjected = time.Now()
+	hasTarget = false
+	go g.watch()
+	return g, nil
+}
+
+// Disable stops the guard, releasing sequences paused by it.
+func (g *Guard) Disable() {
+	injectMutex.Lock()
+	defer injectMutex.Unlock()
+	if activeGuard != g {
+		return
+	}
+	activeGuard = nil
+	close(g.done)
+}
+
+// Reset clears a detected intervention so that a sequence aborted in GuardAbort mode can continue.
+func (g *Guard) Reset() {
+	g.mutex.Lock()
+	g.tripped = false
+	g.mutex.Unlock()
+
+	// Input seen so far must not trip the guard again.
+	injectMutex.Lock()
+	lastInjected = time.Now()
+	hasTarget = false
+	injectMutex.Unlock()
+}
+
+// Check returns ErrUserInterrupted if the user has intervened in GuardAbort
+// mode. In GuardPause mode, it blocks until the user has been quiet for the
+// QuietPeriod or ctx is done.
+func (g *Guard) Check(ctx context.Context) error {
+	for {
+		g.mutex.Lock()
+		tripped := g.tripped
+		remaining := g.opt.QuietPeriod - time.Since(g.lastActivity)
+		g.mutex.Unlock()
+
+		if !tripped {
+			return nil
+		}
+		if g.opt.Mode == GuardAbort {
+			return ErrUserInterrupted
+		}
+		if remaining <= 0 {
+			g.Reset()
+			return nil
+		}
+		timer := time.NewTimer(remaining)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-g.done:
+			timer.Stop()
+			// Disabled by the context given to EnableGuard, or by Disable.
+			return g.ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (g *Guard) watch() {
+	ticker := time.NewTicker(g.opt.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-g.done:
+			return
+		case <-g.ctx.Done():
+			g.Disable()
+			return
+		case <-ticker.C:
+		}
+		if at, ok := g.userActivity(); ok {
+			g.mutex.Lock()
+			if at.After(g.lastActivity) {
+				g.lastActivity = at
+			}
+			g.tripped = true
+			g.mutex.Unlock()
+		}
+	}
+}
+
+// userActivity reports whether input arrived which was not injected by this package, and when.
+func (g *Guard) userActivity() (time.Time, bool) {
+	injectMutex.Lock()
+	since := time.Since(lastInjected)
+	target, targeted := lastTarget, hasTarget
+	injectMutex.Unlock()
+
+	if idle, err := IdleTime(); err == nil && idle+g.opt.Slack < since {
+		return time.Now().Add(-idle), true
+	}
+	if !targeted || since < g.opt.Slack {
+		return time.Time{}, false
+	}
+	pos, err := mpos()
+	if err != nil {
+		return time.Time{}, false
+	}
+	d := pos.Sub(target)
+	if abs(d.X) <= g.opt.Tolerance && abs(d.Y) <= g.opt.Tolerance {
+		return time.Time{}, false
+	}
+	// The cursor was taken over, so stop comparing against the target until the next Mmv.
+	injectMutex.Lock()
+	hasTarget = false
+	injectMutex.Unlock()
+	return time.Now(), true
+}
+
+// guardInput is called before injecting input. release is true for inputs
+// which release a button or key, which are never dropped so nothing is left
+// pressed.
+func guardInput(release bool) error {
+	injectMutex.Lock()
+	g := activeGuard
+	injectMutex.Unlock()
+	if g == nil {
+		return nil
+	}
+	if release && g.opt.Mode == GuardAbort {
+		return nil
+	}
+	return g.Check(g.ctx)
+}
+
+// markInjected records the time of injected input, and the cursor position set by it if target is not nil.
+func markInjected(target *image.Point) {
+	injectMutex.Lock()
+	defer injectMutex.Unlock()
+	lastInjected = time.Now()
+	if target != nil {
+		lastTarget = *target
+		hasTarget = true
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
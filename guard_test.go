@@ -0,0 +1,62 @@
+package robot
+
+import (
+	"context"
+	"image"
+	"testing"
+	"time"
+
+	"github.com/kbinani/robot/key"
+)
+
+func enableTestGuard(t *testing.T, ctx context.Context, mode GuardMode) *Guard {
+	g, err := EnableGuard(ctx, GuardOptions{Mode: mode, QuietPeriod: time.Hour, Interval: time.Hour})
+	if err != nil {
+		t.Skip(err)
+	}
+	t.Cleanup(g.Disable)
+	return g
+}
+
+func trip(g *Guard) {
+	g.mutex.Lock()
+	g.tripped = true
+	g.lastActivity = time.Now()
+	g.mutex.Unlock()
+}
+
+func TestGuardAbortReportsDroppedInput(t *testing.T) {
+	g := enableTestGuard(t, context.Background(), GuardAbort)
+	trip(g)
+	if err := Btn(Left, Down, image.Pt(10, 10)); err != ErrUserInterrupted {
+		t.Errorf("Btn(Down) = %v, want ErrUserInterrupted", err)
+	}
+	if err := Kbd(key.A, Down); err != ErrUserInterrupted {
+		t.Errorf("Kbd(Down) = %v, want ErrUserInterrupted", err)
+	}
+	if err := Kbd(key.A, Up); err != nil {
+		t.Errorf("Kbd(Up) = %v, want nil", err)
+	}
+	g.Reset()
+	if err := g.Check(context.Background()); err != nil {
+		t.Errorf("Check after Reset = %v", err)
+	}
+}
+
+func TestGuardPauseCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	g := enableTestGuard(t, ctx, GuardPause)
+	trip(g)
+	errc := make(chan error, 1)
+	go func() { errc <- Mmv(image.Pt(10, 10)) }()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-errc:
+		if err != context.Canceled {
+			t.Errorf("Mmv = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Mmv stayed paused after the context was cancelled")
+	}
+}
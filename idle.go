@@ -0,0 +1,31 @@
+package robot
+
+import (
+	"context"
+	"time"
+)
+
+// IdleTime returns the time elapsed since the last user input (mouse or keyboard).
+func IdleTime() (time.Duration, error) {
+	return idleTime()
+}
+
+// WaitIdle blocks until nobody has used mouse or keyboard for at least d, or ctx is done.
+func WaitIdle(ctx context.Context, d time.Duration) error {
+	for {
+		idle, err := IdleTime()
+		if err != nil {
+			return err
+		}
+		if idle >= d {
+			return nil
+		}
+		timer := time.NewTimer(d - idle)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package robot
+
+/*
+#cgo LDFLAGS: -framework IOKit -framework CoreFoundation
+#include <IOKit/IOKitLib.h>
+#include <CoreFoundation/CoreFoundation.h>
+
+static int64_t hid_idle_time() {
+	io_service_t service = IOServiceGetMatchingService(kIOMasterPortDefault, IOServiceMatching("IOHIDSystem"));
+	if (!service) {
+		return -1;
+	}
+	CFTypeRef property = IORegistryEntryCreateCFProperty(service, CFSTR("HIDIdleTime"), kCFAllocatorDefault, 0);
+	IOObjectRelease(service);
+	if (property == NULL) {
+		return -1;
+	}
+	int64_t nanoseconds = -1;
+	if (CFGetTypeID(property) == CFNumberGetTypeID()) {
+		CFNumberGetValue((CFNumberRef)property, kCFNumberSInt64Type, &nanoseconds);
+	}
+	CFRelease(property);
+	return nanoseconds;
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"time"
+)
+
+func idleTime() (time.Duration, error) {
+	ns := C.hid_idle_time()
+	if ns < 0 {
+		return 0, errors.New("cannot get HIDIdleTime")
+	}
+	return time.Duration(ns), nil
+}
@@ -0,0 +1,42 @@
+package robot
+
+/*
+#cgo LDFLAGS: -lX11 -lXss
+#include <X11/Xlib.h>
+#include <X11/extensions/scrnsaver.h>
+
+static int query_idle(Display *dpy, unsigned long *idle) {
+	int event, error;
+	if (!XScreenSaverQueryExtension(dpy, &event, &error)) {
+		return 0;
+	}
+	XScreenSaverInfo *info = XScreenSaverAllocInfo();
+	if (info == NULL) {
+		return 0;
+	}
+	Status ok = XScreenSaverQueryInfo(dpy, DefaultRootWindow(dpy), info);
+	*idle = info->idle;
+	XFree(info);
+	return ok;
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"time"
+)
+
+func idleTime() (time.Duration, error) {
+	dpy, err := openDisplay()
+	if err != nil {
+		return 0, err
+	}
+	displayMutex.Lock()
+	defer displayMutex.Unlock()
+	var idle C.ulong
+	if C.query_idle(dpy, &idle) == 0 {
+		return 0, errors.New("MIT-SCREEN-SAVER extension is not available")
+	}
+	return time.Duration(idle) * time.Millisecond, nil
+}
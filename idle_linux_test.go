@@ -0,0 +1,85 @@
+package robot
+
+import (
+	"context"
+	"github.com/kbinani/robot/key"
+	"image"
+	"testing"
+	"time"
+)
+
+func TestIdleTimeResetsOnInput(t *testing.T) {
+	needX(t)
+	if err := Mmv(image.Pt(10, 10)); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(500 * time.Millisecond)
+	idle, err := IdleTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if idle < 400*time.Millisecond {
+		t.Fatalf("idle time is %v after 500ms without input", idle)
+	}
+	if err := Mmv(image.Pt(20, 20)); err != nil {
+		t.Fatal(err)
+	}
+	idle, err = IdleTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if idle >= 400*time.Millisecond {
+		t.Errorf("idle time is %v right after input", idle)
+	}
+}
+
+func TestIdleTimeResetsOnKey(t *testing.T) {
+	needX(t)
+	time.Sleep(500 * time.Millisecond)
+	Kbd(key.Shift, Click)
+	idle, err := IdleTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if idle >= 400*time.Millisecond {
+		t.Errorf("idle time is %v right after a key click", idle)
+	}
+}
+
+func TestWaitIdle(t *testing.T) {
+	needX(t)
+	if err := Mmv(image.Pt(30, 30)); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := WaitIdle(ctx, 300*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 250*time.Millisecond {
+		t.Errorf("WaitIdle returned after %v, before the machine was idle for 300ms", elapsed)
+	}
+}
+
+func TestWaitIdleWhileBusy(t *testing.T) {
+	needX(t)
+	stop := make(chan struct{})
+	defer close(stop)
+	// Input keeps arriving, so the machine never becomes idle.
+	go func() {
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			case <-time.After(50 * time.Millisecond):
+			}
+			Mmv(image.Pt(40+i%2, 40))
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	if err := WaitIdle(ctx, 300*time.Millisecond); err != context.DeadlineExceeded {
+		t.Errorf("WaitIdle returned %v while input was injected", err)
+	}
+}
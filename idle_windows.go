@@ -0,0 +1,29 @@
+package robot
+
+import (
+	"errors"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+var (
+	procGetLastInputInfo = user32.NewProc("GetLastInputInfo")
+	procGetTickCount     = syscall.NewLazyDLL("kernel32.dll").NewProc("GetTickCount")
+)
+
+type lastInputInfo struct {
+	cbSize uint32
+	dwTime uint32
+}
+
+func idleTime() (time.Duration, error) {
+	var info lastInputInfo
+	info.cbSize = uint32(unsafe.Sizeof(info))
+	if ret, _, _ := procGetLastInputInfo.Call(uintptr(unsafe.Pointer(&info))); ret == 0 {
+		return 0, errors.New("GetLastInputInfo failed")
+	}
+	now, _, _ := procGetTickCount.Call()
+	// Both are 32bit tick counts, so the subtraction is correct across wrap around.
+	return time.Duration(uint32(now)-info.dwTime) * time.Millisecond, nil
+}
@@ -0,0 +1,4 @@
+// Package x11 holds the connection to the X server shared by the packages of
+// robot. Xlib keeps a single error handler per process, so the packages must
+// share it, and with it the connection whose errors it records.
+package x11
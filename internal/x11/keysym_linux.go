@@ -0,0 +1,87 @@
+package x11
+
+/*
+#include <X11/Xlib.h>
+#include <X11/keysym.h>
+*/
+import "C"
+
+import (
+	"github.com/kbinani/robot/key"
+)
+
+// keysyms maps key codes to X keysyms. Keys of punctuation are those of the US layout.
+var keysyms = map[key.Code]C.KeySym{
+	key.Back:      C.XK_BackSpace,
+	key.Tab:       C.XK_Tab,
+	key.Clear:     C.XK_Clear,
+	key.Return:    C.XK_Return,
+	key.RReturn:   C.XK_KP_Enter,
+	key.Shift:     C.XK_Shift_L,
+	key.RShift:    C.XK_Shift_R,
+	key.Control:   C.XK_Control_L,
+	key.RCtrl:     C.XK_Control_R,
+	key.Menu:      C.XK_Alt_L,
+	key.Pause:     C.XK_Pause,
+	key.Capital:   C.XK_Caps_Lock,
+	key.Escape:    C.XK_Escape,
+	key.Space:     C.XK_space,
+	key.Prior:     C.XK_Prior,
+	key.Next:      C.XK_Next,
+	key.End:       C.XK_End,
+	key.Home:      C.XK_Home,
+	key.Left:      C.XK_Left,
+	key.Up:        C.XK_Up,
+	key.Right:     C.XK_Right,
+	key.Down:      C.XK_Down,
+	key.Select:    C.XK_Select,
+	key.Print:     C.XK_Print,
+	key.Execute:   C.XK_Execute,
+	key.Snapshot:  C.XK_Print,
+	key.Insert:    C.XK_Insert,
+	key.Delete:    C.XK_Delete,
+	key.Help:      C.XK_Help,
+	key.Start:     C.XK_Super_L,
+	key.Win:       C.XK_Super_L,
+	key.Apps:      C.XK_Menu,
+	key.Multiply:  C.XK_KP_Multiply,
+	key.Add:       C.XK_KP_Add,
+	key.Separator: C.XK_KP_Separator,
+	key.Subtract:  C.XK_KP_Subtract,
+	key.Decimal:   C.XK_KP_Decimal,
+	key.Divide:    C.XK_KP_Divide,
+	key.Numlock:   C.XK_Num_Lock,
+	key.Scroll:    C.XK_Scroll_Lock,
+	key.OemPlus:   C.XK_equal,
+	key.OemComma:  C.XK_comma,
+	key.OemMinus:  C.XK_minus,
+	key.OemPeriod: C.XK_period,
+	key.Oem1:      C.XK_semicolon,
+	key.Oem2:      C.XK_slash,
+	key.Oem3:      C.XK_grave,
+	key.Oem4:      C.XK_bracketleft,
+	key.Oem5:      C.XK_backslash,
+	key.Oem6:      C.XK_bracketright,
+	key.Oem7:      C.XK_apostrophe,
+}
+
+func init() {
+	for c := key.A; c <= key.Z; c++ {
+		keysyms[c] = C.KeySym(C.XK_a + (c - key.A))
+	}
+	for c := key.Digit0; c <= key.Digit9; c++ {
+		keysyms[c] = C.KeySym(C.XK_0 + (c - key.Digit0))
+	}
+	for c := key.Numpad0; c <= key.Numpad9; c++ {
+		keysyms[c] = C.KeySym(C.XK_KP_0 + (c - key.Numpad0))
+	}
+	for c := key.F1; c <= key.F12; c++ {
+		keysyms[c] = C.KeySym(C.XK_F1 + (c - key.F1))
+	}
+}
+
+// Keysym returns the keysym of the key code, as a KeySym of Xlib.
+func Keysym(code key.Code) (uint64, bool) {
+	sym, ok := keysyms[code]
+	return uint64(sym), ok
+}
@@ -0,0 +1,157 @@
+package x11
+
+/*
+#cgo LDFLAGS: -lX11
+#include <stdint.h>
+#include <X11/Xlib.h>
+#include <X11/Xutil.h>
+
+static Display *shared;
+static int last_error;
+
+// record_error records errors of the shared connection. Errors on private
+// connections, e.g. of selecting events on a window destroyed meanwhile, are
+// expected and dropped; the default handler would exit the process.
+static int record_error(Display *dpy, XErrorEvent *e) {
+	if (dpy == shared) {
+		last_error = e->error_code;
+	}
+	return 0;
+}
+
+static void install_error_handler() {
+	XSetErrorHandler(record_error);
+}
+
+static void set_shared(Display *dpy) {
+	shared = dpy;
+}
+
+static int take_error() {
+	int e = last_error;
+	last_error = 0;
+	return e;
+}
+
+static int mask_shift(unsigned long mask) {
+	int shift = 0;
+	while (mask && !(mask & 1)) {
+		mask >>= 1;
+		shift++;
+	}
+	return shift;
+}
+
+static uint8_t mask_value(unsigned long pixel, unsigned long mask, int shift) {
+	unsigned long max = mask >> shift;
+	if (max == 0) {
+		return 0;
+	}
+	return (uint8_t)(((pixel & mask) >> shift) * 255 / max);
+}
+
+// convert_image writes the pixels of img into out as opaque RGBA.
+static void convert_image(XImage *img, uint8_t *out, int stride) {
+	int rs = mask_shift(img->red_mask);
+	int gs = mask_shift(img->green_mask);
+	int bs = mask_shift(img->blue_mask);
+	int fast = img->bits_per_pixel == 32 && img->byte_order == LSBFirst
+		&& img->red_mask == 0xff0000 && img->green_mask == 0xff00 && img->blue_mask == 0xff;
+	for (int y = 0; y < img->height; y++) {
+		uint8_t *dst = out + y * stride;
+		if (fast) {
+			uint8_t *src = (uint8_t *)img->data + y * img->bytes_per_line;
+			for (int x = 0; x < img->width; x++) {
+				dst[4 * x + 0] = src[4 * x + 2];
+				dst[4 * x + 1] = src[4 * x + 1];
+				dst[4 * x + 2] = src[4 * x + 0];
+				dst[4 * x + 3] = 0xff;
+			}
+			continue;
+		}
+		for (int x = 0; x < img->width; x++) {
+			unsigned long pixel = XGetPixel(img, x, y);
+			dst[4 * x + 0] = mask_value(pixel, img->red_mask, rs);
+			dst[4 * x + 1] = mask_value(pixel, img->green_mask, gs);
+			dst[4 * x + 2] = mask_value(pixel, img->blue_mask, bs);
+			dst[4 * x + 3] = 0xff;
+		}
+	}
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"image"
+	"sync"
+	"unsafe"
+)
+
+var (
+	initOnce sync.Once
+	// openMutex guards display, which is nil until a connection succeeds.
+	openMutex sync.Mutex
+	display   *C.Display
+	// Mutex serializes requests sent through the shared connection.
+	Mutex sync.Mutex
+)
+
+// initialize prepares Xlib for use from several threads, before any connection is opened.
+func initialize() {
+	initOnce.Do(func() {
+		C.XInitThreads()
+		C.install_error_handler()
+	})
+}
+
+// Open returns the connection to the X server named by $DISPLAY, shared by
+// the packages. A failure is not remembered, so that a later call succeeds
+// once the server is up. The result is a *Display of Xlib.
+func Open() (unsafe.Pointer, error) {
+	openMutex.Lock()
+	defer openMutex.Unlock()
+	if display != nil {
+		return unsafe.Pointer(display), nil
+	}
+	initialize()
+	dpy := C.XOpenDisplay(nil)
+	if dpy == nil {
+		return nil, errors.New("cannot open X display")
+	}
+	C.set_shared(dpy)
+	display = dpy
+	return unsafe.Pointer(display), nil
+}
+
+// OpenPrivate opens a connection of its own, for reading events in a
+// blocking loop. Protocol errors on it are ignored. Close it with XCloseDisplay.
+func OpenPrivate() (unsafe.Pointer, error) {
+	initialize()
+	dpy := C.XOpenDisplay(nil)
+	if dpy == nil {
+		return nil, errors.New("cannot open X display")
+	}
+	return unsafe.Pointer(dpy), nil
+}
+
+// TakeError returns the code of the last protocol error on the shared
+// connection and clears it. Call XSync before, to receive pending errors.
+// Callers must hold Mutex.
+func TakeError() int {
+	return int(C.take_error())
+}
+
+// ClearErrors waits until the server has processed the requests sent on the
+// shared connection dpy, and drops the errors they caused, so that TakeError
+// afterwards reports errors of later requests only. Callers must hold Mutex.
+func ClearErrors(dpy unsafe.Pointer) {
+	C.XSync((*C.Display)(dpy), C.False)
+	C.take_error()
+}
+
+// ConvertImage writes the pixels of ximage, an *XImage, into img with its top-left corner at p.
+func ConvertImage(ximage unsafe.Pointer, img *image.RGBA, p image.Point) {
+	out := (*C.uint8_t)(unsafe.Pointer(&img.Pix[img.PixOffset(p.X, p.Y)]))
+	C.convert_image((*C.XImage)(ximage), out, C.int(img.Stride))
+}
@@ -0,0 +1,37 @@
+package xvfb
+
+/*
+#cgo LDFLAGS: -lX11
+#include <X11/Xlib.h>
+
+static void fill_root(Display *dpy, int x, int y, int w, int h, unsigned long pixel) {
+	Window root = DefaultRootWindow(dpy);
+	GC gc = XCreateGC(dpy, root, 0, NULL);
+	XSetForeground(dpy, gc, pixel);
+	XFillRectangle(dpy, root, gc, x, y, w, h);
+	XFreeGC(dpy, gc);
+	XSync(dpy, False);
+}
+*/
+import "C"
+
+import (
+	"github.com/kbinani/robot/internal/x11"
+	"image"
+	"image/color"
+)
+
+// Fill paints r of the root window of $DISPLAY with c, which shows where no
+// window covers it. The screen must have the 24-bit TrueColor visual of the
+// servers run by Start.
+func Fill(r image.Rectangle, c color.RGBA) error {
+	p, err := x11.OpenPrivate()
+	if err != nil {
+		return err
+	}
+	dpy := (*C.Display)(p)
+	defer C.XCloseDisplay(dpy)
+	pixel := C.ulong(c.R)<<16 | C.ulong(c.G)<<8 | C.ulong(c.B)
+	C.fill_root(dpy, C.int(r.Min.X), C.int(r.Min.Y), C.int(r.Dx()), C.int(r.Dy()), pixel)
+	return nil
+}
@@ -0,0 +1,294 @@
+package xvfb
+
+/*
+#cgo LDFLAGS: -lX11
+#include <stdlib.h>
+#include <X11/Xlib.h>
+#include <X11/Xatom.h>
+#include <X11/Xutil.h>
+
+static Window create_window(Display *dpy, int x, int y, int w, int h, unsigned long pixel) {
+	XSetWindowAttributes attr = {0};
+	attr.background_pixel = pixel;
+	attr.event_mask = KeyPressMask | KeyReleaseMask | ButtonPressMask | ButtonReleaseMask |
+		PointerMotionMask | EnterWindowMask | StructureNotifyMask;
+	return XCreateWindow(dpy, DefaultRootWindow(dpy), x, y, w, h, 0, CopyFromParent,
+		InputOutput, CopyFromParent, CWBackPixel | CWEventMask, &attr);
+}
+
+static void set_property(Display *dpy, Window w, const char *name, const char *type, int format, void *data, int n) {
+	XChangeProperty(dpy, w, XInternAtom(dpy, name, False), XInternAtom(dpy, type, False),
+		format, PropModeReplace, (unsigned char *)data, n);
+}
+
+static Atom intern(Display *dpy, const char *name) {
+	return XInternAtom(dpy, name, False);
+}
+
+// input_event is an XEvent of a type reported by Window.Events.
+typedef struct {
+	int type;
+	unsigned long keysym;
+	unsigned int keycode, button, state;
+	int x, y, send_event;
+} input_event;
+
+// next_event reads a pending event of the window. It returns 0 when none
+// is pending, 1 for input, 2 when the window was asked to close, 3 when it
+// was mapped, and -1 for other events.
+static int next_event(Display *dpy, Atom delete_window, input_event *out) {
+	XEvent ev;
+	if (XPending(dpy) == 0) {
+		return 0;
+	}
+	XNextEvent(dpy, &ev);
+	out->type = ev.type;
+	out->send_event = ev.xany.send_event;
+	switch (ev.type) {
+	case KeyPress:
+	case KeyRelease:
+		out->keycode = ev.xkey.keycode;
+		out->state = ev.xkey.state;
+		out->keysym = XLookupKeysym(&ev.xkey, (ev.xkey.state & ShiftMask) ? 1 : 0);
+		out->x = ev.xkey.x;
+		out->y = ev.xkey.y;
+		return 1;
+	case ButtonPress:
+	case ButtonRelease:
+		out->button = ev.xbutton.button;
+		out->state = ev.xbutton.state;
+		out->x = ev.xbutton.x;
+		out->y = ev.xbutton.y;
+		return 1;
+	case MotionNotify:
+		out->state = ev.xmotion.state;
+		out->x = ev.xmotion.x;
+		out->y = ev.xmotion.y;
+		return 1;
+	case EnterNotify:
+		out->x = ev.xcrossing.x;
+		out->y = ev.xcrossing.y;
+		return 1;
+	case ClientMessage:
+		if (ev.xclient.format == 32 && (Atom)ev.xclient.data.l[0] == delete_window) {
+			return 2;
+		}
+		break;
+	case MapNotify:
+		return 3;
+	}
+	return -1;
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"github.com/kbinani/robot/internal/x11"
+	"image"
+	"image/color"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// WindowOptions describes a window created by NewWindow.
+type WindowOptions struct {
+	// Bounds is the requested geometry in root coordinates.
+	Bounds image.Rectangle
+	// Title is set as _NET_WM_NAME and WM_NAME.
+	Title string
+	// Class is the class name of WM_CLASS. The instance name is the same.
+	Class string
+	// PID is set as _NET_WM_PID unless it is zero.
+	PID int
+	// Type is the atom of _NET_WM_WINDOW_TYPE, e.g. "_NET_WM_WINDOW_TYPE_DIALOG". Empty sets none.
+	Type string
+	// Color fills the window.
+	Color color.RGBA
+	// DeleteWindow makes the window take part in the WM_DELETE_WINDOW
+	// protocol. It destroys itself when asked to close.
+	DeleteWindow bool
+}
+
+// Event is an input event received by a Window.
+type Event struct {
+	// Type is the X event type, e.g. 2 for KeyPress.
+	Type int
+	// Keysym of key events, as looked up with the shift state of the event.
+	Keysym  uint64
+	Keycode int
+	Button  int
+	// State is the mask of modifiers and buttons held.
+	State uint
+	// Pos is the position in the window.
+	Pos image.Point
+	// SendEvent tells that the event was sent with XSendEvent.
+	SendEvent bool
+}
+
+// X event types reported in Event.Type.
+const (
+	KeyPress      = C.KeyPress
+	KeyRelease    = C.KeyRelease
+	ButtonPress   = C.ButtonPress
+	ButtonRelease = C.ButtonRelease
+	MotionNotify  = C.MotionNotify
+	EnterNotify   = C.EnterNotify
+)
+
+// Window is a top-level window of a test client. It has a connection of its
+// own, which reads its events in the background until Close.
+type Window struct {
+	// ID is the X window id.
+	ID   uint64
+	dpy  *C.Display
+	stop chan struct{}
+	done chan struct{}
+
+	closeOnce sync.Once
+	mutex     sync.Mutex
+	events    []Event
+	mapped    bool
+	destroyed bool
+}
+
+// NewWindow creates and maps a window on $DISPLAY, and waits until it is mapped,
+// by the window manager if one is running.
+func NewWindow(opt WindowOptions) (*Window, error) {
+	p, err := x11.OpenPrivate()
+	if err != nil {
+		return nil, err
+	}
+	dpy := (*C.Display)(p)
+	r := opt.Bounds
+	pixel := C.ulong(opt.Color.R)<<16 | C.ulong(opt.Color.G)<<8 | C.ulong(opt.Color.B)
+	id := C.create_window(dpy, C.int(r.Min.X), C.int(r.Min.Y), C.int(r.Dx()), C.int(r.Dy()), pixel)
+
+	// Window managers place windows freely unless the position was requested.
+	var hints C.XSizeHints
+	hints.flags = C.USPosition | C.USSize
+	hints.x, hints.y = C.int(r.Min.X), C.int(r.Min.Y)
+	hints.width, hints.height = C.int(r.Dx()), C.int(r.Dy())
+	C.XSetWMNormalHints(dpy, id, &hints)
+
+	setString(dpy, id, "_NET_WM_NAME", "UTF8_STRING", opt.Title)
+	setString(dpy, id, "WM_NAME", "STRING", opt.Title)
+	if opt.Class != "" {
+		setString(dpy, id, "WM_CLASS", "STRING", opt.Class+"\x00"+opt.Class+"\x00")
+	}
+	if opt.PID != 0 {
+		setLongs(dpy, id, "_NET_WM_PID", "CARDINAL", C.long(opt.PID))
+	}
+	if opt.Type != "" {
+		setLongs(dpy, id, "_NET_WM_WINDOW_TYPE", "ATOM", C.long(internAtom(dpy, opt.Type)))
+	}
+	deleteWindow := internAtom(dpy, "WM_DELETE_WINDOW")
+	if opt.DeleteWindow {
+		setLongs(dpy, id, "WM_PROTOCOLS", "ATOM", C.long(deleteWindow))
+	}
+	C.XMapWindow(dpy, id)
+	C.XSync(dpy, C.False)
+
+	w := &Window{ID: uint64(id), dpy: dpy, stop: make(chan struct{}), done: make(chan struct{})}
+	go w.run(deleteWindow)
+	deadline := time.Now().Add(5 * time.Second)
+	for !w.isMapped() {
+		if time.Now().After(deadline) {
+			w.Close()
+			return nil, errors.New("xvfb: window was not mapped")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return w, nil
+}
+
+func (w *Window) isMapped() bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.mapped
+}
+
+func (w *Window) run(deleteWindow C.Atom) {
+	defer close(w.done)
+	for {
+		select {
+		case <-w.stop:
+			return
+		default:
+		}
+		var ev C.input_event
+		switch C.next_event(w.dpy, deleteWindow, &ev) {
+		case 0:
+			time.Sleep(5 * time.Millisecond)
+		case 1:
+			w.mutex.Lock()
+			w.events = append(w.events, Event{
+				Type:      int(ev._type),
+				Keysym:    uint64(ev.keysym),
+				Keycode:   int(ev.keycode),
+				Button:    int(ev.button),
+				State:     uint(ev.state),
+				Pos:       image.Pt(int(ev.x), int(ev.y)),
+				SendEvent: ev.send_event != 0,
+			})
+			w.mutex.Unlock()
+		case 2:
+			C.XDestroyWindow(w.dpy, C.Window(w.ID))
+			C.XSync(w.dpy, C.False)
+			w.mutex.Lock()
+			w.destroyed = true
+			w.mutex.Unlock()
+		case 3:
+			w.mutex.Lock()
+			w.mapped = true
+			w.mutex.Unlock()
+		}
+	}
+}
+
+// Events returns the input events received so far.
+func (w *Window) Events() []Event {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return append([]Event{}, w.events...)
+}
+
+// Destroyed reports whether the window destroyed itself after WM_DELETE_WINDOW.
+func (w *Window) Destroyed() bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.destroyed
+}
+
+// Close closes the connection of the window, which destroys it. It may be called more than once.
+func (w *Window) Close() {
+	w.closeOnce.Do(func() {
+		close(w.stop)
+		<-w.done
+		C.XCloseDisplay(w.dpy)
+	})
+}
+
+func internAtom(dpy *C.Display, name string) C.Atom {
+	s := C.CString(name)
+	defer C.free(unsafe.Pointer(s))
+	return C.intern(dpy, s)
+}
+
+func setString(dpy *C.Display, w C.Window, name, typ, value string) {
+	n, t := C.CString(name), C.CString(typ)
+	defer C.free(unsafe.Pointer(n))
+	defer C.free(unsafe.Pointer(t))
+	v := C.CBytes([]byte(value))
+	defer C.free(v)
+	C.set_property(dpy, w, n, t, 8, v, C.int(len(value)))
+}
+
+// setLongs sets a property of format 32, whose items Xlib passes as long.
+func setLongs(dpy *C.Display, w C.Window, name, typ string, values ...C.long) {
+	n, t := C.CString(name), C.CString(typ)
+	defer C.free(unsafe.Pointer(n))
+	defer C.free(unsafe.Pointer(t))
+	C.set_property(dpy, w, n, t, 32, unsafe.Pointer(&values[0]), C.int(len(values)))
+}
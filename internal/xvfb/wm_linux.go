@@ -0,0 +1,350 @@
+package xvfb
+
+/*
+#include <stdlib.h>
+#include <X11/Xlib.h>
+#include <X11/Xatom.h>
+#include <X11/Xutil.h>
+
+static Atom wm_atom(Display *dpy, const char *name) {
+	return XInternAtom(dpy, name, False);
+}
+
+// list_has reports whether the list property prop of w of the given type contains value.
+static int list_has(Display *dpy, Window w, const char *prop, Atom type, unsigned long value) {
+	Atom actual;
+	int format;
+	unsigned long count, remaining, i;
+	unsigned char *data = NULL;
+	int found = 0;
+	if (XGetWindowProperty(dpy, w, wm_atom(dpy, prop), 0, 1 << 16, False, type,
+			&actual, &format, &count, &remaining, &data) != Success || data == NULL) {
+		return 0;
+	}
+	for (i = 0; i < count; i++) {
+		if (((unsigned long *)data)[i] == value) {
+			found = 1;
+		}
+	}
+	XFree(data);
+	return found;
+}
+
+// list_change adds value to, or removes it from, the list property prop of w.
+static void list_change(Display *dpy, Window w, const char *prop, Atom type, unsigned long value, int add) {
+	Atom actual;
+	int format;
+	unsigned long count = 0, remaining, i;
+	unsigned char *data = NULL;
+	long *items;
+	int n = 0;
+	XGetWindowProperty(dpy, w, wm_atom(dpy, prop), 0, 1 << 16, False, type,
+		&actual, &format, &count, &remaining, &data);
+	items = calloc(count + 1, sizeof(long));
+	for (i = 0; data != NULL && i < count; i++) {
+		if (((unsigned long *)data)[i] != value) {
+			items[n++] = ((long *)data)[i];
+		}
+	}
+	if (data != NULL) {
+		XFree(data);
+	}
+	if (add) {
+		items[n++] = value;
+	}
+	XChangeProperty(dpy, w, wm_atom(dpy, prop), type, 32, PropModeReplace, (unsigned char *)items, n);
+	free(items);
+}
+
+static int wm_supports(Display *dpy, Atom a) {
+	return list_has(dpy, DefaultRootWindow(dpy), "_NET_SUPPORTED", XA_ATOM, a);
+}
+
+static void set_wm_state(Display *dpy, Window w, long state) {
+	long data[2] = {state, None};
+	Atom a = wm_atom(dpy, "WM_STATE");
+	XChangeProperty(dpy, w, a, a, 32, PropModeReplace, (unsigned char *)data, 2);
+}
+
+// get_cardinal returns the first item of the CARDINAL property prop of w, or def when it is not set.
+static long get_cardinal(Display *dpy, Window w, const char *prop, long def) {
+	Atom actual;
+	int format;
+	unsigned long count, remaining;
+	unsigned char *data = NULL;
+	long value = def;
+	if (XGetWindowProperty(dpy, w, wm_atom(dpy, prop), 0, 1, False, XA_CARDINAL,
+			&actual, &format, &count, &remaining, &data) == Success && data != NULL) {
+		if (count > 0) {
+			value = ((long *)data)[0];
+		}
+		XFree(data);
+	}
+	return value;
+}
+
+static void set_cardinal(Display *dpy, Window w, const char *prop, long value) {
+	XChangeProperty(dpy, w, wm_atom(dpy, prop), XA_CARDINAL, 32, PropModeReplace, (unsigned char *)&value, 1);
+}
+
+static void set_active(Display *dpy, Window w) {
+	long data = w;
+	XChangeProperty(dpy, DefaultRootWindow(dpy), wm_atom(dpy, "_NET_ACTIVE_WINDOW"), XA_WINDOW,
+		32, PropModeReplace, (unsigned char *)&data, 1);
+}
+
+// forget removes w from the client list, and clears the active window if it was w.
+static void forget(Display *dpy, Window w) {
+	Window root = DefaultRootWindow(dpy);
+	list_change(dpy, root, "_NET_CLIENT_LIST", XA_WINDOW, w, 0);
+	if (list_has(dpy, root, "_NET_ACTIVE_WINDOW", XA_WINDOW, w)) {
+		set_active(dpy, None);
+	}
+}
+
+static void wm_map(Display *dpy, Window w) {
+	Window root = DefaultRootWindow(dpy);
+	XMapWindow(dpy, w);
+	set_wm_state(dpy, w, NormalState);
+	if (wm_supports(dpy, wm_atom(dpy, "_NET_WM_DESKTOP")) && get_cardinal(dpy, w, "_NET_WM_DESKTOP", -1) == -1) {
+		set_cardinal(dpy, w, "_NET_WM_DESKTOP", get_cardinal(dpy, root, "_NET_CURRENT_DESKTOP", 0));
+	}
+	list_change(dpy, w, "_NET_WM_STATE", XA_ATOM, wm_atom(dpy, "_NET_WM_STATE_HIDDEN"), 0);
+	if (!list_has(dpy, root, "_NET_CLIENT_LIST", XA_WINDOW, w)) {
+		list_change(dpy, root, "_NET_CLIENT_LIST", XA_WINDOW, w, 1);
+	}
+}
+
+static void wm_client_message(Display *dpy, XClientMessageEvent *e) {
+	Window w = e->window;
+	Atom type = e->message_type;
+	int i;
+	if (!wm_supports(dpy, type) && type != wm_atom(dpy, "WM_CHANGE_STATE")) {
+		return;
+	}
+	if (type == wm_atom(dpy, "_NET_WM_STATE")) {
+		for (i = 1; i <= 2; i++) {
+			Atom a = e->data.l[i];
+			if (a == None || !wm_supports(dpy, a)) {
+				continue;
+			}
+			int add = e->data.l[0] == 1 || (e->data.l[0] == 2 && !list_has(dpy, w, "_NET_WM_STATE", XA_ATOM, a));
+			list_change(dpy, w, "_NET_WM_STATE", XA_ATOM, a, add);
+		}
+	} else if (type == wm_atom(dpy, "_NET_ACTIVE_WINDOW")) {
+		wm_map(dpy, w);
+		XRaiseWindow(dpy, w);
+		XSetInputFocus(dpy, w, RevertToPointerRoot, CurrentTime);
+		set_active(dpy, w);
+	} else if (type == wm_atom(dpy, "WM_CHANGE_STATE")) {
+		if (e->data.l[0] == IconicState) {
+			// WM_STATE is set first, so that the UnmapNotify does not withdraw the window.
+			set_wm_state(dpy, w, IconicState);
+			if (wm_supports(dpy, wm_atom(dpy, "_NET_WM_STATE_HIDDEN"))) {
+				list_change(dpy, w, "_NET_WM_STATE", XA_ATOM, wm_atom(dpy, "_NET_WM_STATE_HIDDEN"), 1);
+			}
+			XUnmapWindow(dpy, w);
+		}
+	} else if (type == wm_atom(dpy, "_NET_CLOSE_WINDOW")) {
+		Atom deleteWindow = wm_atom(dpy, "WM_DELETE_WINDOW");
+		if (list_has(dpy, w, "WM_PROTOCOLS", XA_ATOM, deleteWindow)) {
+			XEvent ev = {0};
+			ev.xclient.type = ClientMessage;
+			ev.xclient.window = w;
+			ev.xclient.message_type = wm_atom(dpy, "WM_PROTOCOLS");
+			ev.xclient.format = 32;
+			ev.xclient.data.l[0] = deleteWindow;
+			ev.xclient.data.l[1] = CurrentTime;
+			XSendEvent(dpy, w, False, NoEventMask, &ev);
+		} else {
+			XKillClient(dpy, w);
+		}
+	} else if (type == wm_atom(dpy, "_NET_MOVERESIZE_WINDOW")) {
+		XMoveResizeWindow(dpy, w, e->data.l[1], e->data.l[2], e->data.l[3], e->data.l[4]);
+	} else if (type == wm_atom(dpy, "_NET_CURRENT_DESKTOP")) {
+		long n = e->data.l[0];
+		if (n >= 0 && n < get_cardinal(dpy, w, "_NET_NUMBER_OF_DESKTOPS", 0)) {
+			set_cardinal(dpy, w, "_NET_CURRENT_DESKTOP", n);
+		}
+	} else if (type == wm_atom(dpy, "_NET_WM_DESKTOP")) {
+		// 0xFFFFFFFF, sent as -1 in a long, puts the window on every desktop.
+		set_cardinal(dpy, w, "_NET_WM_DESKTOP", e->data.l[0] & 0xffffffff);
+	}
+}
+
+// wm_step handles a pending event. It returns 0 when none is pending.
+static int wm_step(Display *dpy) {
+	XEvent ev;
+	if (XPending(dpy) == 0) {
+		return 0;
+	}
+	XNextEvent(dpy, &ev);
+	switch (ev.type) {
+	case MapRequest:
+		wm_map(dpy, ev.xmaprequest.window);
+		break;
+	case ConfigureRequest: {
+		XConfigureRequestEvent *e = &ev.xconfigurerequest;
+		XWindowChanges changes = {0};
+		changes.x = e->x;
+		changes.y = e->y;
+		changes.width = e->width;
+		changes.height = e->height;
+		changes.border_width = e->border_width;
+		changes.sibling = e->above;
+		changes.stack_mode = e->detail;
+		XConfigureWindow(dpy, e->window, e->value_mask, &changes);
+		break;
+	}
+	case UnmapNotify: {
+		Atom actual;
+		int format;
+		unsigned long count, remaining;
+		unsigned char *data = NULL;
+		Atom a = wm_atom(dpy, "WM_STATE");
+		int iconic = 0;
+		if (XGetWindowProperty(dpy, ev.xunmap.window, a, 0, 2, False, a,
+				&actual, &format, &count, &remaining, &data) == Success && data != NULL) {
+			iconic = count > 0 && ((long *)data)[0] == IconicState;
+			XFree(data);
+		}
+		if (!iconic) {
+			forget(dpy, ev.xunmap.window);
+		}
+		break;
+	}
+	case DestroyNotify:
+		forget(dpy, ev.xdestroywindow.window);
+		break;
+	case ClientMessage:
+		wm_client_message(dpy, &ev.xclient);
+		break;
+	}
+	XSync(dpy, False);
+	return 1;
+}
+
+static void wm_start(Display *dpy, long *supported, int n, Window check) {
+	Window root = DefaultRootWindow(dpy);
+	long data = check;
+	XSelectInput(dpy, root, SubstructureRedirectMask | SubstructureNotifyMask);
+	XChangeProperty(dpy, root, wm_atom(dpy, "_NET_SUPPORTED"), XA_ATOM, 32, PropModeReplace, (unsigned char *)supported, n);
+	XChangeProperty(dpy, root, wm_atom(dpy, "_NET_SUPPORTING_WM_CHECK"), XA_WINDOW, 32, PropModeReplace, (unsigned char *)&data, 1);
+	XChangeProperty(dpy, check, wm_atom(dpy, "_NET_SUPPORTING_WM_CHECK"), XA_WINDOW, 32, PropModeReplace, (unsigned char *)&data, 1);
+	XChangeProperty(dpy, root, wm_atom(dpy, "_NET_CLIENT_LIST"), XA_WINDOW, 32, PropModeReplace, NULL, 0);
+	set_active(dpy, None);
+	XSync(dpy, False);
+}
+
+static void wm_stop(Display *dpy) {
+	Window root = DefaultRootWindow(dpy);
+	const char *props[] = {"_NET_SUPPORTED", "_NET_SUPPORTING_WM_CHECK", "_NET_CLIENT_LIST", "_NET_ACTIVE_WINDOW",
+		"_NET_NUMBER_OF_DESKTOPS", "_NET_DESKTOP_NAMES", "_NET_CURRENT_DESKTOP"};
+	int i;
+	for (i = 0; i < 7; i++) {
+		XDeleteProperty(dpy, root, wm_atom(dpy, props[i]));
+	}
+	XSync(dpy, False);
+}
+*/
+import "C"
+
+import (
+	"github.com/kbinani/robot/internal/x11"
+	"time"
+	"unsafe"
+)
+
+// EWMH lists the hints a WM supports by default.
+var EWMH = []string{
+	"_NET_SUPPORTED",
+	"_NET_SUPPORTING_WM_CHECK",
+	"_NET_CLIENT_LIST",
+	"_NET_ACTIVE_WINDOW",
+	"_NET_CLOSE_WINDOW",
+	"_NET_MOVERESIZE_WINDOW",
+	"_NET_WM_STATE",
+	"_NET_WM_STATE_HIDDEN",
+	"_NET_WM_STATE_MAXIMIZED_VERT",
+	"_NET_WM_STATE_MAXIMIZED_HORZ",
+	"_NET_WM_STATE_FULLSCREEN",
+	"_NET_WM_STATE_ABOVE",
+	"_NET_WM_STATE_MODAL",
+	"_NET_NUMBER_OF_DESKTOPS",
+	"_NET_DESKTOP_NAMES",
+	"_NET_CURRENT_DESKTOP",
+	"_NET_WM_DESKTOP",
+}
+
+// WM is a minimal window manager for tests. It does not draw frames or
+// place windows. It keeps _NET_CLIENT_LIST and _NET_ACTIVE_WINDOW, and
+// handles the client messages of the hints it supports, by changing
+// _NET_WM_STATE, focusing, iconifying, closing and moving windows, and
+// switching desktops.
+type WM struct {
+	dpy  *C.Display
+	stop chan struct{}
+	done chan struct{}
+}
+
+// StartWM starts managing the screen of $DISPLAY, announcing supported in
+// _NET_SUPPORTED. Windows mapped before are not managed.
+func StartWM(supported ...string) (*WM, error) {
+	p, err := x11.OpenPrivate()
+	if err != nil {
+		return nil, err
+	}
+	dpy := (*C.Display)(p)
+	atoms := make([]C.long, len(supported)+1)
+	for i, name := range supported {
+		atoms[i] = C.long(internAtom(dpy, name))
+	}
+	check := C.XCreateSimpleWindow(dpy, C.XDefaultRootWindow(dpy), -1, -1, 1, 1, 0, 0, 0)
+	C.wm_start(dpy, (*C.long)(unsafe.Pointer(&atoms[0])), C.int(len(supported)), check)
+	wm := &WM{dpy: dpy, stop: make(chan struct{}), done: make(chan struct{})}
+	go wm.run()
+	return wm, nil
+}
+
+func (wm *WM) run() {
+	defer close(wm.done)
+	for {
+		select {
+		case <-wm.stop:
+			return
+		default:
+		}
+		if C.wm_step(wm.dpy) == 0 {
+			time.Sleep(2 * time.Millisecond)
+		}
+	}
+}
+
+// SetDesktops announces count virtual desktops, named by names, which may be
+// fewer, and shows the first.
+func (wm *WM) SetDesktops(count int, names ...string) {
+	root := C.XDefaultRootWindow(wm.dpy)
+	setLongs(wm.dpy, root, "_NET_NUMBER_OF_DESKTOPS", "CARDINAL", C.long(count))
+	setLongs(wm.dpy, root, "_NET_CURRENT_DESKTOP", "CARDINAL", 0)
+	value := ""
+	for _, name := range names {
+		value += name + "\x00"
+	}
+	setString(wm.dpy, root, "_NET_DESKTOP_NAMES", "UTF8_STRING", value)
+	C.XSync(wm.dpy, C.False)
+}
+
+// SetFrameExtents sets _NET_FRAME_EXTENTS of the window w, as a window
+// manager drawing decorations of these widths would. The window itself is not moved.
+func (wm *WM) SetFrameExtents(w uint64, left, right, top, bottom int) {
+	setLongs(wm.dpy, C.Window(w), "_NET_FRAME_EXTENTS", "CARDINAL", C.long(left), C.long(right), C.long(top), C.long(bottom))
+	C.XSync(wm.dpy, C.False)
+}
+
+// Stop stops managing windows and removes the hints from the root window.
+func (wm *WM) Stop() {
+	close(wm.stop)
+	<-wm.done
+	C.wm_stop(wm.dpy)
+	C.XCloseDisplay(wm.dpy)
+}
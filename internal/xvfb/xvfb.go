@@ -0,0 +1,68 @@
+// Package xvfb starts a virtual X server for the tests of the robot packages.
+package xvfb
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Server is a running Xvfb.
+type Server struct {
+	// Display is the display name, e.g. ":99".
+	Display string
+	cmd     *exec.Cmd
+}
+
+// Start runs Xvfb with a screen of the given size on a free display, and
+// waits until it accepts connections. It returns exec.ErrNotFound when Xvfb
+// is not installed.
+func Start(width, height int, args ...string) (*Server, error) {
+	path, err := exec.LookPath("Xvfb")
+	if err != nil {
+		return nil, exec.ErrNotFound
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	// Xvfb picks a free display and writes its number to fd 3 when it is ready.
+	args = append([]string{"-displayfd", "3", "-screen", "0", fmt.Sprintf("%dx%dx24", width, height), "-nolisten", "tcp"}, args...)
+	cmd := exec.Command(path, args...)
+	cmd.ExtraFiles = []*os.File{w}
+	if err := cmd.Start(); err != nil {
+		w.Close()
+		return nil, err
+	}
+	w.Close()
+
+	number := make(chan string, 1)
+	go func() {
+		line, _ := bufio.NewReader(r).ReadString('\n')
+		number <- strings.TrimSpace(line)
+	}()
+	select {
+	case n := <-number:
+		if n == "" {
+			cmd.Process.Kill()
+			cmd.Wait()
+			return nil, errors.New("xvfb: server exited before it was ready")
+		}
+		return &Server{Display: ":" + n, cmd: cmd}, nil
+	case <-time.After(10 * time.Second):
+		cmd.Process.Kill()
+		cmd.Wait()
+		return nil, errors.New("xvfb: server did not start")
+	}
+}
+
+// Stop terminates the server.
+func (s *Server) Stop() {
+	s.cmd.Process.Kill()
+	s.cmd.Wait()
+}
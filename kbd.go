@@ -1,25 +1,138 @@
 package robot
 
 import (
+	"fmt"
 	"github.com/kbinani/robot/key"
 )
 
-// Kbd changes key statuses of keyboaard.
-func Kbd(code key.Code, op Op) {
+// Kbd changes key statuses of keyboaard. It returns an error when the key
+// is not supported, or when a Guard holds the input back.
+func Kbd(code key.Code, op Op) error {
 	nativeKeyCode := nativeKeyCode(code)
-	if nativeKeyCode <= 0 {
-		return
+	if nativeKeyCode < 0 {
+		return fmt.Errorf("robot: key %d is not supported", code)
 	}
+	if err := guardInput(op == Up); err != nil {
+		return err
+	}
+	markInjected(nil)
+	defer markInjected(nil)
 	if op != Up {
 		setKeyboardStatus(nativeKeyCode, true)
 	}
 	if op != Down {
 		setKeyboardStatus(nativeKeyCode, false)
 	}
+	return nil
+}
+
+// Type types text by clicking keys one by one, as on a US keyboard layout.
+// Supported characters are printable ASCII, tab and newline. When text
+// contains other characters, nothing is typed and an error is returned. When a Guard
+// aborts typing, ErrUserInterrupted is returned.
+func Type(text string) error {
+	for _, r := range text {
+		if _, _, ok := KeyForRune(r); !ok {
+			return fmt.Errorf("cannot type %q", r)
+		}
+	}
+	for _, r := range text {
+		if err := guardInput(false); err != nil {
+			return err
+		}
+		code, shift, _ := KeyForRune(r)
+		if shift {
+			if err := Kbd(key.Shift, Down); err != nil {
+				return err
+			}
+		}
+		err := Kbd(code, Click)
+		if shift {
+			// Released even after an error, so that shift is not left pressed.
+			Kbd(key.Shift, Up)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// KeyForRune returns the key which types r on a US keyboard layout, and
+// whether shift must be held down while clicking it. ok is false when r
+// cannot be typed by Type.
+func KeyForRune(r rune) (code key.Code, shift bool, ok bool) {
+	switch {
+	case 'a' <= r && r <= 'z':
+		return key.A + key.Code(r-'a'), false, true
+	case 'A' <= r && r <= 'Z':
+		return key.A + key.Code(r-'A'), true, true
+	case '0' <= r && r <= '9':
+		return key.Digit0 + key.Code(r-'0'), false, true
+	case r == ' ':
+		return key.Space, false, true
+	case r == '\t':
+		return key.Tab, false, true
+	case r == '\n':
+		return key.Return, false, true
+	}
+	for _, k := range punctuationKeys {
+		if r == k.plain && r != 0 {
+			return k.code, false, true
+		}
+		if r == k.shifted {
+			return k.code, true, true
+		}
+	}
+	return 0, false, false
+}
+
+// punctuationKeys lists the keys of a US keyboard layout typing symbols, with
+// the characters typed without and with shift. Digits are listed for their
+// shifted symbols only.
+var punctuationKeys = []struct {
+	code    key.Code
+	plain   rune
+	shifted rune
+}{
+	{key.Digit1, 0, '!'},
+	{key.Digit2, 0, '@'},
+	{key.Digit3, 0, '#'},
+	{key.Digit4, 0, '$'},
+	{key.Digit5, 0, '%'},
+	{key.Digit6, 0, '^'},
+	{key.Digit7, 0, '&'},
+	{key.Digit8, 0, '*'},
+	{key.Digit9, 0, '('},
+	{key.Digit0, 0, ')'},
+	{key.OemMinus, '-', '_'},
+	{key.OemPlus, '=', '+'},
+	{key.Oem4, '[', '{'},
+	{key.Oem6, ']', '}'},
+	{key.Oem5, '\\', '|'},
+	{key.Oem1, ';', ':'},
+	{key.Oem7, '\'', '"'},
+	{key.Oem3, '`', '~'},
+	{key.OemComma, ',', '<'},
+	{key.OemPeriod, '.', '>'},
+	{key.Oem2, '/', '?'},
+}
+
+// NativeKeyCode returns the key code of the platform for code: the virtual
+// key code on Windows, the kVK code on macOS, where key.Command is returned
+// as the CGEventFlags mask instead, and the keycode of the current keyboard
+// mapping on X11. ok is false when the key is not supported. Zero is a valid
+// code, e.g. kVK_ANSI_A.
+func NativeKeyCode(code key.Code) (n int, ok bool) {
+	n = nativeKeyCode(code)
+	return n, n >= 0
 }
 
 func IsKbdDown(code key.Code) bool {
 	nativeKeyCode := nativeKeyCode(code)
+	if nativeKeyCode < 0 {
+		return false
+	}
 	return isKeyboardDown(nativeKeyCode)
 }
 
@@ -120,6 +120,26 @@ func nativeKeyCode(code key.Code) int {
 		return C.kVK_ANSI_Y
 	case key.Z:
 		return C.kVK_ANSI_Z
+	case key.Digit0:
+		return C.kVK_ANSI_0
+	case key.Digit1:
+		return C.kVK_ANSI_1
+	case key.Digit2:
+		return C.kVK_ANSI_2
+	case key.Digit3:
+		return C.kVK_ANSI_3
+	case key.Digit4:
+		return C.kVK_ANSI_4
+	case key.Digit5:
+		return C.kVK_ANSI_5
+	case key.Digit6:
+		return C.kVK_ANSI_6
+	case key.Digit7:
+		return C.kVK_ANSI_7
+	case key.Digit8:
+		return C.kVK_ANSI_8
+	case key.Digit9:
+		return C.kVK_ANSI_9
 	case key.Alt:
 		return C.kVK_Option
 	case key.Ctrl:
@@ -162,12 +182,36 @@ func nativeKeyCode(code key.Code) int {
 		return C.kVK_Help
 	case key.Separator:
 		return C.kVK_ANSI_Comma
+	case key.Multiply:
+		return C.kVK_ANSI_KeypadMultiply
+	case key.Add:
+		return C.kVK_ANSI_KeypadPlus
 	case key.Subtract:
-		return C.kVK_ANSI_Minus
+		return C.kVK_ANSI_KeypadMinus
 	case key.Decimal:
-		return C.kVK_ANSI_Period
+		return C.kVK_ANSI_KeypadDecimal
 	case key.Divide:
-		return C.kVK_ANSI_Slash
+		return C.kVK_ANSI_KeypadDivide
+	case key.Numpad0:
+		return C.kVK_ANSI_Keypad0
+	case key.Numpad1:
+		return C.kVK_ANSI_Keypad1
+	case key.Numpad2:
+		return C.kVK_ANSI_Keypad2
+	case key.Numpad3:
+		return C.kVK_ANSI_Keypad3
+	case key.Numpad4:
+		return C.kVK_ANSI_Keypad4
+	case key.Numpad5:
+		return C.kVK_ANSI_Keypad5
+	case key.Numpad6:
+		return C.kVK_ANSI_Keypad6
+	case key.Numpad7:
+		return C.kVK_ANSI_Keypad7
+	case key.Numpad8:
+		return C.kVK_ANSI_Keypad8
+	case key.Numpad9:
+		return C.kVK_ANSI_Keypad9
 	case key.F1:
 		return C.kVK_F1
 	case key.F2:
@@ -199,11 +243,27 @@ func nativeKeyCode(code key.Code) int {
 	case key.VolumeUp:
 		return C.kVK_VolumeUp
 	case key.OemPlus:
-		return C.kVK_ANSI_KeypadPlus
+		return C.kVK_ANSI_Equal
+	case key.OemComma:
+		return C.kVK_ANSI_Comma
 	case key.OemMinus:
-		return C.kVK_ANSI_KeypadMinus
+		return C.kVK_ANSI_Minus
 	case key.OemPeriod:
-		return C.kVK_ANSI_KeypadDecimal
+		return C.kVK_ANSI_Period
+	case key.Oem1:
+		return C.kVK_ANSI_Semicolon
+	case key.Oem2:
+		return C.kVK_ANSI_Slash
+	case key.Oem3:
+		return C.kVK_ANSI_Grave
+	case key.Oem4:
+		return C.kVK_ANSI_LeftBracket
+	case key.Oem5:
+		return C.kVK_ANSI_Backslash
+	case key.Oem6:
+		return C.kVK_ANSI_RightBracket
+	case key.Oem7:
+		return C.kVK_ANSI_Quote
 	case key.Command:
 		return C.kCGEventFlagMaskCommand
 	}
@@ -0,0 +1,88 @@
+package robot
+
+/*
+#cgo LDFLAGS: -lX11 -lXtst
+#include <X11/Xlib.h>
+#include <X11/extensions/XTest.h>
+*/
+import "C"
+
+import (
+	"github.com/kbinani/robot/internal/x11"
+	"github.com/kbinani/robot/key"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+func setKeyboardStatus(nativeKeyCode int, down bool) {
+	dpy, err := openDisplay()
+	if err != nil {
+		return
+	}
+	displayMutex.Lock()
+	defer displayMutex.Unlock()
+	if !hasXTest(dpy) {
+		return
+	}
+	press := C.Bool(C.False)
+	if down {
+		press = C.True
+	}
+	C.XTestFakeKeyEvent(dpy, C.uint(nativeKeyCode), press, C.CurrentTime)
+	C.XFlush(dpy)
+}
+
+// nativeKeyCode returns the X keycode which has the keysym of code in the current keyboard mapping.
+func nativeKeyCode(code key.Code) int {
+	sym, ok := x11.Keysym(code)
+	if !ok {
+		return -1
+	}
+	dpy, err := openDisplay()
+	if err != nil {
+		return -1
+	}
+	displayMutex.Lock()
+	defer displayMutex.Unlock()
+	keycode := C.XKeysymToKeycode(dpy, C.KeySym(sym))
+	if keycode == 0 {
+		return -1
+	}
+	return int(keycode)
+}
+
+func isKeyboardDown(code int) bool {
+	dpy, err := openDisplay()
+	if err != nil {
+		return false
+	}
+	displayMutex.Lock()
+	defer displayMutex.Unlock()
+	// The keymap is a bit vector indexed by keycode.
+	var keys [32]C.char
+	C.XQueryKeymap(dpy, &keys[0])
+	return code < 256 && byte(keys[code/8])&(1<<uint(code%8)) != 0
+}
+
+// backlightBrightness reads the keyboard backlight LED in sysfs, which is present on laptops only.
+func backlightBrightness() float32 {
+	dirs, _ := filepath.Glob("/sys/class/leds/*kbd_backlight")
+	for _, dir := range dirs {
+		value, err1 := readSysfsInt(filepath.Join(dir, "brightness"))
+		max, err2 := readSysfsInt(filepath.Join(dir, "max_brightness"))
+		if err1 == nil && err2 == nil && max > 0 {
+			return float32(value) / float32(max)
+		}
+	}
+	return -1
+}
+
+func readSysfsInt(path string) (int, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(b)))
+}
@@ -31,6 +31,16 @@ const (
 	X                 Code = 88
 	Y                 Code = 89
 	Z                 Code = 90
+	Digit0            Code = 48
+	Digit1            Code = 49
+	Digit2            Code = 50
+	Digit3            Code = 51
+	Digit4            Code = 52
+	Digit5            Code = 53
+	Digit6            Code = 54
+	Digit7            Code = 55
+	Digit8            Code = 56
+	Digit9            Code = 57
 	Start             Code = 92
 	// Win               Code = 91
 	Alt               Code = 18
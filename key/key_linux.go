@@ -0,0 +1,6 @@
+package key
+
+const (
+	Win               Code = 91
+	Command           Code = Control
+)
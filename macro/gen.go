@@ -0,0 +1,323 @@
+package macro
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/kbinani/robot"
+	"github.com/kbinani/robot/key"
+	"go/format"
+	"strconv"
+	"time"
+)
+
+// Options controls the source code generated by Generate.
+type Options struct {
+	// Package is the package name of the generated file. Default is "main".
+	Package string
+	// Func is the name of the generated function. Default is "Replay", or "TestReplay" when Test is true.
+	Func string
+	// Test generates a test function taking *testing.T, which fails when an action returns an error.
+	Test bool
+	// KeepMoves keeps every recorded cursor movement. By default only the
+	// last position of consecutive movements is kept, and movements to the
+	// position of a following button action are dropped.
+	KeepMoves bool
+	// Resolution is the unit waits are rounded to. Default is 10ms.
+	Resolution time.Duration
+	// MinWait is the shortest wait emitted; shorter pauses are dropped. Default is 50ms.
+	MinWait time.Duration
+	// MaxWait caps long pauses, e.g. when the tester was away. Default is 3s.
+	MaxWait time.Duration
+}
+
+// typingPause is the shortest pause which splits coalesced keystrokes into separate robot.Type calls.
+const typingPause = time.Second
+
+type stepKind int
+
+const (
+	moveStep stepKind = iota
+	btnStep
+	kbdStep
+	typeStep
+)
+
+type step struct {
+	kind  stepKind
+	start time.Duration
+	end   time.Duration
+	event Event
+	text  string
+}
+
+// Generate returns formatted Go source code which replays events with robot.Mmv, robot.Btn, robot.Kbd and robot.Type.
+func Generate(events []Event, opt Options) ([]byte, error) {
+	opt = opt.withDefaults()
+	steps := coalesceText(dropMoves(foldClicks(events), opt.KeepMoves))
+
+	var body bytes.Buffer
+	usesImage := false
+	usesKey := false
+	usesTime := false
+	var last time.Duration
+	for i, s := range steps {
+		if i > 0 {
+			if wait := opt.wait(s.start - last); wait > 0 {
+				fmt.Fprintf(&body, "time.Sleep(%s)\n", durationLiteral(wait))
+				usesTime = true
+			}
+		}
+		last = s.end
+
+		e := s.event
+		switch s.kind {
+		case moveStep:
+			call := fmt.Sprintf("robot.Mmv(image.Pt(%d, %d))", e.Pos.X, e.Pos.Y)
+			body.WriteString(opt.checked(call))
+			usesImage = true
+		case btnStep:
+			call := fmt.Sprintf("robot.Btn(%s, %s, image.Pt(%d, %d))", buttonName(e.Button), opName(e.Op), e.Pos.X, e.Pos.Y)
+			body.WriteString(opt.checked(call))
+			usesImage = true
+		case kbdStep:
+			call := fmt.Sprintf("robot.Kbd(%s, %s)", keyName(e.Key), opName(e.Op))
+			body.WriteString(opt.checked(call))
+			usesKey = true
+		case typeStep:
+			call := fmt.Sprintf("robot.Type(%s)", strconv.Quote(s.text))
+			body.WriteString(opt.checked(call))
+		}
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "package %s\n\n", opt.Package)
+	src.WriteString("import (\n")
+	src.WriteString("\"github.com/kbinani/robot\"\n")
+	if usesKey {
+		src.WriteString("\"github.com/kbinani/robot/key\"\n")
+	}
+	if usesImage {
+		src.WriteString("\"image\"\n")
+	}
+	if opt.Test {
+		src.WriteString("\"testing\"\n")
+	}
+	if usesTime {
+		src.WriteString("\"time\"\n")
+	}
+	src.WriteString(")\n\n")
+	if opt.Test {
+		fmt.Fprintf(&src, "func %s(t *testing.T) {\n", opt.Func)
+	} else {
+		fmt.Fprintf(&src, "// %s replays a recorded session.\n", opt.Func)
+		fmt.Fprintf(&src, "func %s() {\n", opt.Func)
+	}
+	src.Write(body.Bytes())
+	src.WriteString("}\n")
+
+	return format.Source(src.Bytes())
+}
+
+func (opt Options) withDefaults() Options {
+	if opt.Package == "" {
+		opt.Package = "main"
+	}
+	if opt.Func == "" {
+		if opt.Test {
+			opt.Func = "TestReplay"
+		} else {
+			opt.Func = "Replay"
+		}
+	}
+	if opt.Resolution <= 0 {
+		opt.Resolution = 10 * time.Millisecond
+	}
+	if opt.MinWait <= 0 {
+		opt.MinWait = 50 * time.Millisecond
+	}
+	if opt.MaxWait <= 0 {
+		opt.MaxWait = 3 * time.Second
+	}
+	return opt
+}
+
+func (opt Options) wait(d time.Duration) time.Duration {
+	d = d.Round(opt.Resolution)
+	if d < opt.MinWait {
+		return 0
+	}
+	if d > opt.MaxWait {
+		return opt.MaxWait
+	}
+	return d
+}
+
+func (opt Options) checked(call string) string {
+	if opt.Test {
+		return fmt.Sprintf("if err := %s; err != nil {\nt.Fatal(err)\n}\n", call)
+	}
+	return call + "\n"
+}
+
+// foldClicks merges a Down immediately followed by the matching Up into a single Click.
+func foldClicks(events []Event) []step {
+	steps := []step{}
+	for i := 0; i < len(events); i++ {
+		e := events[i]
+		s := step{start: e.Time, end: e.Time, event: e}
+		switch e.Kind {
+		case Move:
+			s.kind = moveStep
+		case Button:
+			s.kind = btnStep
+		case Key:
+			s.kind = kbdStep
+		}
+		if e.Kind != Move && e.Op == robot.Down && i+1 < len(events) {
+			next := events[i+1]
+			if next.Kind == e.Kind && next.Op == robot.Up && next.Key == e.Key && next.Button == e.Button && next.Pos == e.Pos {
+				s.event.Op = robot.Click
+				s.end = next.Time
+				i++
+			}
+		}
+		steps = append(steps, s)
+	}
+	return steps
+}
+
+// dropMoves removes cursor movements which do not change the outcome of the replay.
+func dropMoves(steps []step, keep bool) []step {
+	if keep {
+		return steps
+	}
+	result := []step{}
+	for i, s := range steps {
+		if s.kind == moveStep && i+1 < len(steps) {
+			next := steps[i+1]
+			if next.kind == moveStep || (next.kind == btnStep && next.event.Pos == s.event.Pos) {
+				continue
+			}
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+// coalesceText merges runs of key clicks, optionally wrapped by shift, into a single Type step.
+func coalesceText(steps []step) []step {
+	result := []step{}
+	for i := 0; i < len(steps); {
+		text, shifted, n := typedRun(steps[i:])
+		count := len([]rune(text))
+		if count >= 2 || (count == 1 && shifted) {
+			result = append(result, step{kind: typeStep, start: steps[i].start, end: steps[i+n-1].end, text: text})
+			i += n
+			continue
+		}
+		result = append(result, steps[i])
+		i++
+	}
+	return result
+}
+
+// typedRun returns the text typed by the longest prefix of steps which can be
+// replaced by robot.Type, and the number of steps in the prefix.
+func typedRun(steps []step) (text string, shifted bool, n int) {
+	var runes []rune
+	shift := false
+	usedShift := false
+	for i, s := range steps {
+		if s.kind != kbdStep || (i > 0 && s.start-steps[i-1].end >= typingPause) {
+			break
+		}
+		e := s.event
+		if e.Key == key.Shift && e.Op == robot.Down && !shift {
+			shift = true
+			continue
+		}
+		if e.Key == key.Shift && e.Op == robot.Up && shift {
+			shift = false
+			text, shifted, n = string(runes), usedShift, i+1
+			continue
+		}
+		r, ok := runeForKey(e.Key, shift)
+		if e.Op != robot.Click || !ok {
+			break
+		}
+		runes = append(runes, r)
+		usedShift = usedShift || shift
+		if !shift {
+			text, shifted, n = string(runes), usedShift, i+1
+		}
+	}
+	return text, shifted, n
+}
+
+var typedRunes = map[key.Code][2]rune{}
+
+func init() {
+	for r := rune(0); r < 0x80; r++ {
+		code, shift, ok := robot.KeyForRune(r)
+		if !ok {
+			continue
+		}
+		pair := typedRunes[code]
+		if shift {
+			pair[1] = r
+		} else {
+			pair[0] = r
+		}
+		typedRunes[code] = pair
+	}
+}
+
+func runeForKey(code key.Code, shift bool) (rune, bool) {
+	pair, ok := typedRunes[code]
+	if !ok {
+		return 0, false
+	}
+	r := pair[0]
+	if shift {
+		r = pair[1]
+	}
+	return r, r != 0
+}
+
+func durationLiteral(d time.Duration) string {
+	if d%time.Second == 0 {
+		return fmt.Sprintf("%d * time.Second", d/time.Second)
+	}
+	return fmt.Sprintf("%d * time.Millisecond", d/time.Millisecond)
+}
+
+func buttonName(b robot.Button) string {
+	switch b {
+	case robot.Left:
+		return "robot.Left"
+	case robot.Right:
+		return "robot.Right"
+	case robot.Middle:
+		return "robot.Middle"
+	}
+	return fmt.Sprintf("robot.Button(%d)", b)
+}
+
+func opName(op robot.Op) string {
+	switch op {
+	case robot.Click:
+		return "robot.Click"
+	case robot.Down:
+		return "robot.Down"
+	case robot.Up:
+		return "robot.Up"
+	}
+	return fmt.Sprintf("robot.Op(%d)", op)
+}
+
+func keyName(code key.Code) string {
+	if name, ok := keyNames[code]; ok {
+		return "key." + name
+	}
+	return fmt.Sprintf("key.Code(%d)", code)
+}
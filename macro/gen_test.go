@@ -0,0 +1,85 @@
+package macro
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+var goldenTests = []struct {
+	golden string
+	input  string
+	opt    Options
+}{
+	{"login.golden", "login.jsonl", Options{}},
+	{"login_test.golden", "login.jsonl", Options{Package: "replay", Test: true, Func: "TestLogin"}},
+	{"drag.golden", "drag.jsonl", Options{}},
+	{"drag_keepmoves.golden", "drag.jsonl", Options{KeepMoves: true, MinWait: 10 * time.Millisecond}},
+}
+
+func TestGenerateGolden(t *testing.T) {
+	for _, tt := range goldenTests {
+		t.Run(tt.golden, func(t *testing.T) {
+			f, err := os.Open(filepath.Join("testdata", tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			events, err := Decode(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Generate(events, tt.opt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join("testdata", tt.golden)
+			if *update {
+				if err := os.WriteFile(path, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("generated source differs from %s; run go test -update after checking:\n%s", path, got)
+			}
+		})
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "drag.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	events, err := Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := Encode(&buf, events); err != nil {
+		t.Fatal(err)
+	}
+	again, err := Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again) != len(events) {
+		t.Fatalf("got %d events, want %d", len(again), len(events))
+	}
+	for i := range events {
+		if again[i] != events[i] {
+			t.Errorf("event %d: got %+v, want %+v", i, again[i], events[i])
+		}
+	}
+}
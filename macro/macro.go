@@ -0,0 +1,71 @@
+// Package macro turns recorded input sessions into Go source code which replays them with package robot.
+package macro
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/kbinani/robot"
+	"github.com/kbinani/robot/key"
+	"image"
+	"io"
+	"strings"
+	"time"
+)
+
+// Kind represents type of recorded input events.
+type Kind int
+
+// Kinds of recorded input events.
+const (
+	Move Kind = iota
+	Button
+	Key
+)
+
+// Event is a recorded input event.
+type Event struct {
+	// Time is the offset of the event from the start of the recording.
+	Time time.Duration `json:"time"`
+	Kind Kind          `json:"kind"`
+	// Pos is the cursor position, used by Move and Button events.
+	Pos    image.Point  `json:"pos"`
+	Button robot.Button `json:"button"`
+	// Op is Down or Up for Button and Key events. Click is also accepted.
+	Op  robot.Op `json:"op"`
+	Key key.Code `json:"key"`
+}
+
+// Decode reads a recorded event stream, which is one JSON encoded Event per line.
+func Decode(r io.Reader) ([]Event, error) {
+	events := []Event{}
+	s := bufio.NewScanner(r)
+	line := 0
+	for s.Scan() {
+		line++
+		text := strings.TrimSpace(s.Text())
+		if text == "" {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal([]byte(text), &e); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		events = append(events, e)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Encode writes events in the format read by Decode.
+func Encode(w io.Writer, events []Event) error {
+	enc := json.NewEncoder(w)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package macro
+
+import (
+	"github.com/kbinani/robot/key"
+)
+
+// keyNames maps key codes to the identifiers declared in package key.
+// Where several identifiers share a code, the first declared one is used.
+var keyNames = map[key.Code]string{
+	key.A:                 "A",
+	key.B:                 "B",
+	key.C:                 "C",
+	key.D:                 "D",
+	key.E:                 "E",
+	key.F:                 "F",
+	key.G:                 "G",
+	key.H:                 "H",
+	key.I:                 "I",
+	key.J:                 "J",
+	key.K:                 "K",
+	key.L:                 "L",
+	key.M:                 "M",
+	key.N:                 "N",
+	key.O:                 "O",
+	key.P:                 "P",
+	key.Q:                 "Q",
+	key.R:                 "R",
+	key.S:                 "S",
+	key.T:                 "T",
+	key.U:                 "U",
+	key.V:                 "V",
+	key.W:                 "W",
+	key.X:                 "X",
+	key.Y:                 "Y",
+	key.Z:                 "Z",
+	key.Digit0:            "Digit0",
+	key.Digit1:            "Digit1",
+	key.Digit2:            "Digit2",
+	key.Digit3:            "Digit3",
+	key.Digit4:            "Digit4",
+	key.Digit5:            "Digit5",
+	key.Digit6:            "Digit6",
+	key.Digit7:            "Digit7",
+	key.Digit8:            "Digit8",
+	key.Digit9:            "Digit9",
+	key.Start:             "Start",
+	key.Alt:               "Alt",
+	key.Ctrl:              "Ctrl",
+	key.RCtrl:             "RCtrl",
+	key.Esc:               "Esc",
+	key.Back:              "Back",
+	key.Tab:               "Tab",
+	key.Clear:             "Clear",
+	key.Return:            "Return",
+	key.RReturn:           "RReturn",
+	key.Shift:             "Shift",
+	key.RShift:            "RShift",
+	key.Pause:             "Pause",
+	key.Capital:           "Capital",
+	key.Kana:              "Kana",
+	key.Final:             "Final",
+	key.Kanji:             "Kanji",
+	key.Convert:           "Convert",
+	key.Nonconvert:        "Nonconvert",
+	key.Accept:            "Accept",
+	key.Modechange:        "Modechange",
+	key.Space:             "Space",
+	key.Prior:             "Prior",
+	key.Next:              "Next",
+	key.End:               "End",
+	key.Home:              "Home",
+	key.Left:              "Left",
+	key.Up:                "Up",
+	key.Right:             "Right",
+	key.Down:              "Down",
+	key.Select:            "Select",
+	key.Print:             "Print",
+	key.Execute:           "Execute",
+	key.Snapshot:          "Snapshot",
+	key.Insert:            "Insert",
+	key.Delete:            "Delete",
+	key.Help:              "Help",
+	key.Apps:              "Apps",
+	key.Multiply:          "Multiply",
+	key.Add:               "Add",
+	key.Separator:         "Separator",
+	key.Subtract:          "Subtract",
+	key.Decimal:           "Decimal",
+	key.Divide:            "Divide",
+	key.Numpad0:           "Numpad0",
+	key.Numpad1:           "Numpad1",
+	key.Numpad2:           "Numpad2",
+	key.Numpad3:           "Numpad3",
+	key.Numpad4:           "Numpad4",
+	key.Numpad5:           "Numpad5",
+	key.Numpad6:           "Numpad6",
+	key.Numpad7:           "Numpad7",
+	key.Numpad8:           "Numpad8",
+	key.Numpad9:           "Numpad9",
+	key.F1:                "F1",
+	key.F2:                "F2",
+	key.F3:                "F3",
+	key.F4:                "F4",
+	key.F5:                "F5",
+	key.F6:                "F6",
+	key.F7:                "F7",
+	key.F8:                "F8",
+	key.F9:                "F9",
+	key.F10:               "F10",
+	key.F11:               "F11",
+	key.F12:               "F12",
+	key.Numlock:           "Numlock",
+	key.Scroll:            "Scroll",
+	key.Sleep:             "Sleep",
+	key.BrowserBack:       "BrowserBack",
+	key.BrowserForward:    "BrowserForward",
+	key.BrowserRefresh:    "BrowserRefresh",
+	key.BrowserStop:       "BrowserStop",
+	key.BrowserSearch:     "BrowserSearch",
+	key.BrowserFavorites:  "BrowserFavorites",
+	key.BrowserHome:       "BrowserHome",
+	key.VolumeMute:        "VolumeMute",
+	key.VolumeDown:        "VolumeDown",
+	key.VolumeUp:          "VolumeUp",
+	key.MediaNextTrack:    "MediaNextTrack",
+	key.MediaPrevTrack:    "MediaPrevTrack",
+	key.MediaStop:         "MediaStop",
+	key.MediaPlayPause:    "MediaPlayPause",
+	key.LaunchMediaSelect: "LaunchMediaSelect",
+	key.LaunchMail:        "LaunchMail",
+	key.LaunchApp1:        "LaunchApp1",
+	key.LaunchApp2:        "LaunchApp2",
+	key.OemPlus:           "OemPlus",
+	key.OemComma:          "OemComma",
+	key.OemMinus:          "OemMinus",
+	key.OemPeriod:         "OemPeriod",
+	key.Oem1:              "Oem1",
+	key.Oem2:              "Oem2",
+	key.Oem3:              "Oem3",
+	key.Oem4:              "Oem4",
+	key.Oem5:              "Oem5",
+	key.Oem6:              "Oem6",
+	key.Oem7:              "Oem7",
+	key.Oem8:              "Oem8",
+	key.OemReset:          "OemReset",
+	key.OemJump:           "OemJump",
+	key.OemPA1:            "OemPA1",
+	key.OemPA2:            "OemPA2",
+	key.OemPA3:            "OemPA3",
+}
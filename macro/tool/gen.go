@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/kbinani/robot/macro"
+	"os"
+)
+
+// Converts a recorded event stream into Go source code.
+//
+//	go run gen.go -test -func TestLogin < login.jsonl > login_test.go
+func main() {
+	var opt macro.Options
+	flag.StringVar(&opt.Package, "pkg", "main", "package name of the generated file")
+	flag.StringVar(&opt.Func, "func", "", "name of the generated function")
+	flag.BoolVar(&opt.Test, "test", false, "generate a test function")
+	flag.BoolVar(&opt.KeepMoves, "keep-moves", false, "keep every cursor movement")
+	flag.DurationVar(&opt.MinWait, "min-wait", 0, "drop waits shorter than this")
+	flag.DurationVar(&opt.MaxWait, "max-wait", 0, "cap waits longer than this")
+	flag.Parse()
+
+	in := os.Stdin
+	if flag.NArg() > 0 {
+		f, err := os.Open(flag.Arg(0))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		in = f
+	}
+	events, err := macro.Decode(in)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	src, err := macro.Generate(events, opt)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Stdout.Write(src)
+}
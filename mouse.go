@@ -2,10 +2,17 @@ package robot
 
 import (
 	"image"
+	"sync"
+	"time"
 )
 
 // Mmv moves mouse cursor to specified position.
 func Mmv(pos image.Point) error {
+	if err := guardInput(false); err != nil {
+		return err
+	}
+	markInjected(&pos)
+	defer markInjected(&pos)
 	return mmv(pos)
 }
 
@@ -14,7 +21,55 @@ func Mpos() (image.Point, error) {
 	return mpos()
 }
 
-// Btn operates mouse buttons.
-func Btn(button Button, operation Op, pos image.Point) {
-	btn(button, operation, pos)
+// Btn operates mouse buttons. It returns an error when a Guard holds the
+// input back, or when the operation cannot be performed.
+func Btn(button Button, operation Op, pos image.Point) error {
+	if err := guardInput(operation == Up); err != nil {
+		return err
+	}
+	markInjected(&pos)
+	defer markInjected(&pos)
+	if err := btn(button, operation, pos); err != nil {
+		return err
+	}
+	notifyBtn(BtnEvent{Button: button, Op: operation, Pos: pos, Time: time.Now()})
+	return nil
+}
+
+// BtnEvent describes a mouse button operation performed by Btn.
+type BtnEvent struct {
+	Button Button
+	Op     Op
+	Pos    image.Point
+	Time   time.Time
+}
+
+var (
+	btnListenersMutex sync.Mutex
+	btnListeners      = map[chan<- BtnEvent]bool{}
+)
+
+// NotifyBtn causes Btn to relay its operations to c. Sends do not block, so events are dropped when c is not ready.
+func NotifyBtn(c chan<- BtnEvent) {
+	btnListenersMutex.Lock()
+	defer btnListenersMutex.Unlock()
+	btnListeners[c] = true
+}
+
+// StopNotifyBtn stops relaying to c.
+func StopNotifyBtn(c chan<- BtnEvent) {
+	btnListenersMutex.Lock()
+	defer btnListenersMutex.Unlock()
+	delete(btnListeners, c)
+}
+
+func notifyBtn(e BtnEvent) {
+	btnListenersMutex.Lock()
+	defer btnListenersMutex.Unlock()
+	for c := range btnListeners {
+		select {
+		case c <- e:
+		default:
+		}
+	}
 }
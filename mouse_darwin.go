@@ -12,6 +12,7 @@ import "C"
 
 import (
 	"errors"
+	"fmt"
 	"image"
 )
 
@@ -41,38 +42,37 @@ func mpos() (image.Point, error) {
 	return image.Pt(int(loc.x), int(loc.y)), nil
 }
 
-func btn(btn Button, operation Op, pos image.Point) {
+func btn(btn Button, operation Op, pos image.Point) error {
 	var mouseButton C.CGMouseButton
-	mouseButton = C.kCGMouseButtonLeft
+	var downType, upType C.CGEventType
 	switch btn {
 	case Left:
-		mouseButton = C.kCGMouseButtonLeft
+		mouseButton, downType, upType = C.kCGMouseButtonLeft, C.kCGEventLeftMouseDown, C.kCGEventLeftMouseUp
 	case Right:
-		mouseButton = C.kCGMouseButtonRight
+		mouseButton, downType, upType = C.kCGMouseButtonRight, C.kCGEventRightMouseDown, C.kCGEventRightMouseUp
 	case Middle:
-		mouseButton = C.kCGMouseButtonCenter
+		mouseButton, downType, upType = C.kCGMouseButtonCenter, C.kCGEventOtherMouseDown, C.kCGEventOtherMouseUp
+	default:
+		return fmt.Errorf("robot: button %d is not supported", btn)
 	}
 	p := C.CGPointMake(C.CGFloat(pos.X), C.CGFloat(pos.Y))
-	if operation != Down {
-		var eventType C.CGEventType
-		if btn == Right {
-			eventType = C.kCGEventRightMouseDown
-		} else {
-			eventType = C.kCGEventLeftMouseDown
-		}
-		down := C.CGEventCreateMouseEvent(0, eventType, p, mouseButton)
-		defer C.releaseCGEvent(down)
-		C.CGEventPost(C.kCGHIDEventTap, down)
-	}
 	if operation != Up {
-		var eventType C.CGEventType
-		if btn == Right {
-			eventType = C.kCGEventRightMouseUp
-		} else {
-			eventType = C.kCGEventLeftMouseUp
+		if err := postMouseEvent(downType, p, mouseButton); err != nil {
+			return err
 		}
-		up := C.CGEventCreateMouseEvent(0, eventType, p, mouseButton)
-		defer C.releaseCGEvent(up)
-		C.CGEventPost(C.kCGHIDEventTap, up)
 	}
+	if operation != Down {
+		return postMouseEvent(upType, p, mouseButton)
+	}
+	return nil
+}
+
+func postMouseEvent(eventType C.CGEventType, p C.CGPoint, mouseButton C.CGMouseButton) error {
+	event := C.CGEventCreateMouseEvent(0, eventType, p, mouseButton)
+	if event == 0 {
+		return errors.New("cannot create mouse event")
+	}
+	defer C.releaseCGEvent(event)
+	C.CGEventPost(C.kCGHIDEventTap, event)
+	return nil
 }
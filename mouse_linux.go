@@ -0,0 +1,98 @@
+package robot
+
+/*
+#cgo LDFLAGS: -lX11 -lXtst
+#include <X11/Xlib.h>
+#include <X11/extensions/XTest.h>
+
+static int has_xtest(Display *dpy) {
+	int event, error, major, minor;
+	return XTestQueryExtension(dpy, &event, &error, &major, &minor);
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"image"
+)
+
+var errNoXTest = errors.New("XTEST extension is not available")
+
+// hasXTest reports whether the server can fake input. Callers must hold displayMutex.
+func hasXTest(dpy *C.Display) bool {
+	return C.has_xtest(dpy) != 0
+}
+
+func mmv(pos image.Point) error {
+	dpy, err := openDisplay()
+	if err != nil {
+		return err
+	}
+	displayMutex.Lock()
+	defer displayMutex.Unlock()
+	return fakeMotion(dpy, pos)
+}
+
+// fakeMotion moves the cursor to pos, in coordinates of the root window spanning all monitors. Callers must hold displayMutex.
+func fakeMotion(dpy *C.Display, pos image.Point) error {
+	if !hasXTest(dpy) {
+		return errNoXTest
+	}
+	clearXErrors(dpy)
+	// Screen -1 is the screen the cursor is on.
+	C.XTestFakeMotionEvent(dpy, -1, C.int(pos.X), C.int(pos.Y), C.CurrentTime)
+	C.XSync(dpy, C.False)
+	if takeXError() != 0 {
+		return errors.New("XTestFakeMotionEvent failed")
+	}
+	return nil
+}
+
+func mpos() (image.Point, error) {
+	dpy, err := openDisplay()
+	if err != nil {
+		return image.Point{}, err
+	}
+	displayMutex.Lock()
+	defer displayMutex.Unlock()
+	var root, child C.Window
+	var rootX, rootY, winX, winY C.int
+	var mask C.uint
+	if C.XQueryPointer(dpy, C.XDefaultRootWindow(dpy), &root, &child, &rootX, &rootY, &winX, &winY, &mask) == 0 {
+		return image.Point{}, errors.New("cursor is on another screen")
+	}
+	return image.Pt(int(rootX), int(rootY)), nil
+}
+
+func btn(button Button, op Op, pos image.Point) error {
+	var number C.uint
+	switch button {
+	case Left:
+		number = C.Button1
+	case Middle:
+		number = C.Button2
+	case Right:
+		number = C.Button3
+	default:
+		return fmt.Errorf("robot: button %d is not supported", button)
+	}
+	dpy, err := openDisplay()
+	if err != nil {
+		return err
+	}
+	displayMutex.Lock()
+	defer displayMutex.Unlock()
+	if err := fakeMotion(dpy, pos); err != nil {
+		return err
+	}
+	if op != Up {
+		C.XTestFakeButtonEvent(dpy, number, C.True, C.CurrentTime)
+	}
+	if op != Down {
+		C.XTestFakeButtonEvent(dpy, number, C.False, C.CurrentTime)
+	}
+	C.XFlush(dpy)
+	return nil
+}
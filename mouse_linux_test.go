@@ -0,0 +1,80 @@
+package robot
+
+import (
+	"github.com/kbinani/robot/key"
+	"image"
+	"testing"
+)
+
+func TestMmvMpos(t *testing.T) {
+	needX(t)
+	for _, p := range []image.Point{{0, 0}, {100, 200}, {1279, 799}} {
+		if err := Mmv(p); err != nil {
+			t.Fatal(err)
+		}
+		got, err := Mpos()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != p {
+			t.Errorf("Mpos() = %v after Mmv(%v)", got, p)
+		}
+	}
+}
+
+func TestBtnMovesCursor(t *testing.T) {
+	needX(t)
+	p := image.Pt(321, 123)
+	if err := Btn(Left, Click, p); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Mpos()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != p {
+		t.Errorf("Mpos() = %v after clicking at %v", got, p)
+	}
+}
+
+func TestBtnNotifiesOnlyPerformed(t *testing.T) {
+	c := make(chan BtnEvent, 1)
+	NotifyBtn(c)
+	defer StopNotifyBtn(c)
+	if err := Btn(Button(9), Click, image.Pt(10, 10)); err == nil {
+		t.Error("Btn accepted an unknown button")
+	}
+	select {
+	case e := <-c:
+		t.Errorf("failed Btn notified %+v", e)
+	default:
+	}
+
+	needX(t)
+	if err := Btn(Left, Click, image.Pt(10, 10)); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case e := <-c:
+		if e.Button != Left || e.Op != Click || e.Pos != image.Pt(10, 10) {
+			t.Errorf("notified %+v", e)
+		}
+	default:
+		t.Error("Btn did not notify")
+	}
+}
+
+func TestKbdDownUp(t *testing.T) {
+	needX(t)
+	if _, ok := NativeKeyCode(key.A); !ok {
+		t.Fatal("key A is not on the keyboard mapping")
+	}
+	Kbd(key.Shift, Down)
+	if !IsKbdDown(key.Shift) {
+		t.Error("shift is not down after Kbd(key.Shift, Down)")
+	}
+	Kbd(key.Shift, Up)
+	if IsKbdDown(key.Shift) {
+		t.Error("shift is down after Kbd(key.Shift, Up)")
+	}
+}
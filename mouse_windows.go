@@ -1,6 +1,8 @@
 package robot
 
 import (
+	"errors"
+	"fmt"
 	"github.com/kbinani/win"
 	"image"
 )
@@ -16,29 +18,26 @@ func mpos() (image.Point, error) {
 	return image.Pt(int(pos.X), int(pos.Y)), nil
 }
 
-func btn(button Button, op Op, pos image.Point) {
-	Mmv(pos)
-
+func btn(button Button, op Op, pos image.Point) error {
+	var down, up win.DWORD
+	switch button {
+	case Left:
+		down, up = win.MOUSEEVENTF_LEFTDOWN, win.MOUSEEVENTF_LEFTUP
+	case Right:
+		down, up = win.MOUSEEVENTF_RIGHTDOWN, win.MOUSEEVENTF_RIGHTUP
+	case Middle:
+		down, up = win.MOUSEEVENTF_MIDDLEDOWN, win.MOUSEEVENTF_MIDDLEUP
+	default:
+		return fmt.Errorf("robot: button %d is not supported", button)
+	}
+	if !win.SetCursorPos(int32(pos.X), int32(pos.Y)) {
+		return errors.New("SetCursorPos failed")
+	}
 	if op != Up {
-		var down win.DWORD
-		if button == Left {
-			down = win.MOUSEEVENTF_LEFTDOWN
-		} else if button == Right {
-			down = win.MOUSEEVENTF_RIGHTDOWN
-		} else if button == Middle {
-			down = win.MOUSEEVENTF_MIDDLEDOWN
-		}
 		win.Mouse_event(down, 0, 0, 0, nil)
 	}
 	if op != Down {
-		var up win.DWORD
-		if button == Left {
-			up = win.MOUSEEVENTF_LEFTUP
-		} else if button == Right {
-			up = win.MOUSEEVENTF_RIGHTUP
-		} else if button == Middle {
-			up = win.MOUSEEVENTF_MIDDLEUP
-		}
 		win.Mouse_event(up, 0, 0, 0, nil)
 	}
+	return nil
 }
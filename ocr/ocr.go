@@ -0,0 +1,121 @@
+// Package ocr recognizes text on screen, for apps which do not expose their labels through accessibility APIs.
+package ocr
+
+import (
+	"github.com/kbinani/robot"
+	"image"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Word is a recognized word.
+type Word struct {
+	Text string
+	// Rect is the bounding box of the word in the coordinates of the recognized image, i.e. screen coordinates for captures.
+	Rect image.Rectangle
+	// Confidence is from 0 to 1.
+	Confidence float64
+	// Line identifies the text line the word belongs to. Words of the same line have the same value.
+	Line int
+}
+
+// Center returns the center of the word, which can be passed to robot.Btn or robot.Mmv.
+func (w Word) Center() image.Point {
+	return image.Pt((w.Rect.Min.X+w.Rect.Max.X)/2, (w.Rect.Min.Y+w.Rect.Max.Y)/2)
+}
+
+// Engine recognizes words in images.
+type Engine interface {
+	Recognize(img image.Image) ([]Word, error)
+}
+
+// DefaultEngine is the engine used by FindText.
+var DefaultEngine Engine = &Tesseract{}
+
+// FindText captures region and returns the bounding boxes of text matching
+// the regular expression pattern, in screen coordinates. pattern is matched
+// against each line of text, with words joined by a space, so it can span
+// words, e.g. "Save As".
+func FindText(region image.Rectangle, pattern string) ([]image.Rectangle, error) {
+	img, err := robot.Capture(region)
+	if err != nil {
+		return nil, err
+	}
+	found, err := FindTextIn(DefaultEngine, img, pattern)
+	if err != nil {
+		return nil, err
+	}
+	rects := []image.Rectangle{}
+	for _, w := range found {
+		rects = append(rects, w.Rect)
+	}
+	return rects, nil
+}
+
+// FindTextIn recognizes img with engine, and returns the text matching pattern as in FindText.
+// The confidence of a match is the lowest confidence of its words.
+func FindTextIn(engine Engine, img image.Image, pattern string) ([]Word, error) {
+	r, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	words, err := engine.Recognize(img)
+	if err != nil {
+		return nil, err
+	}
+	found := []Word{}
+	for _, line := range lines(words) {
+		// starts[i] is the offset of line[i] in text.
+		starts := []int{}
+		texts := []string{}
+		offset := 0
+		for _, w := range line {
+			starts = append(starts, offset)
+			texts = append(texts, w.Text)
+			offset += len(w.Text) + 1
+		}
+		text := strings.Join(texts, " ")
+		for _, m := range r.FindAllStringIndex(text, -1) {
+			match := Word{Text: text[m[0]:m[1]], Confidence: 1, Line: line[0].Line}
+			for i, w := range line {
+				if starts[i]+len(w.Text) <= m[0] || m[1] <= starts[i] {
+					continue
+				}
+				if match.Rect.Empty() {
+					match.Rect = w.Rect
+				} else {
+					match.Rect = match.Rect.Union(w.Rect)
+				}
+				if w.Confidence < match.Confidence {
+					match.Confidence = w.Confidence
+				}
+			}
+			if !match.Rect.Empty() {
+				found = append(found, match)
+			}
+		}
+	}
+	return found, nil
+}
+
+// lines groups words by line, ordering words of each line from left to right.
+func lines(words []Word) [][]Word {
+	index := map[int]int{}
+	result := [][]Word{}
+	for _, w := range words {
+		i, ok := index[w.Line]
+		if !ok {
+			i = len(result)
+			index[w.Line] = i
+			result = append(result, nil)
+		}
+		result[i] = append(result[i], w)
+	}
+	for _, line := range result {
+		sort.SliceStable(line, func(i, j int) bool {
+			return line[i].Rect.Min.X < line[j].Rect.Min.X
+		})
+	}
+	return result
+}
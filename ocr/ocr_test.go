@@ -0,0 +1,61 @@
+package ocr
+
+import (
+	"errors"
+	"image"
+	"reflect"
+	"testing"
+)
+
+// words is an Engine returning fixed words.
+type words []Word
+
+func (w words) Recognize(img image.Image) ([]Word, error) {
+	if w == nil {
+		return nil, errors.New("no words")
+	}
+	return w, nil
+}
+
+func TestFindTextIn(t *testing.T) {
+	// Words of a line are out of order, as engines do not promise any.
+	engine := words{
+		{Text: "As...", Rect: image.Rect(50, 10, 80, 20), Confidence: 0.8, Line: 1},
+		{Text: "Save", Rect: image.Rect(10, 10, 40, 20), Confidence: 0.9, Line: 1},
+		{Text: "Save", Rect: image.Rect(10, 40, 40, 50), Confidence: 0.7, Line: 2},
+		{Text: "As", Rect: image.Rect(10, 70, 30, 80), Confidence: 0.6, Line: 3},
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	tests := []struct {
+		pattern string
+		want    []Word
+	}{
+		{"Save As", []Word{{Text: "Save As", Rect: image.Rect(10, 10, 80, 20), Confidence: 0.8, Line: 1}}},
+		{"^Save$", []Word{{Text: "Save", Rect: image.Rect(10, 40, 40, 50), Confidence: 0.7, Line: 2}}},
+		// A match within a word gives the box of the whole word.
+		{"av", []Word{
+			{Text: "av", Rect: image.Rect(10, 10, 40, 20), Confidence: 0.9, Line: 1},
+			{Text: "av", Rect: image.Rect(10, 40, 40, 50), Confidence: 0.7, Line: 2},
+		}},
+		{"Open", []Word{}},
+	}
+	for _, tt := range tests {
+		got, err := FindTextIn(engine, img, tt.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("FindTextIn(%q) = %v, want %v", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestFindTextInErrors(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	if _, err := FindTextIn(words{}, img, "("); err == nil {
+		t.Error("invalid pattern was accepted")
+	}
+	if _, err := FindTextIn(words(nil), img, "a"); err == nil {
+		t.Error("engine error was not returned")
+	}
+}
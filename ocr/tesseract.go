@@ -0,0 +1,115 @@
+package ocr
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Tesseract recognizes text by running the tesseract command, which must be installed.
+type Tesseract struct {
+	// Path of the tesseract command. Default is "tesseract", looked up in $PATH.
+	Path string
+	// Lang is the list of languages, e.g. "eng+jpn". Default is tesseract's default.
+	Lang string
+	// PSM is the page segmentation mode. Default is 11, sparse text, which suits UI labels.
+	PSM int
+	// Scale enlarges images before recognition, as tesseract is tuned for
+	// text larger than typical UI fonts. Default is 2.
+	Scale int
+}
+
+// Recognize implements Engine.
+func (t *Tesseract) Recognize(img image.Image) ([]Word, error) {
+	path := t.Path
+	if path == "" {
+		path = "tesseract"
+	}
+	psm := t.PSM
+	if psm <= 0 {
+		psm = 11
+	}
+	scale := t.Scale
+	if scale <= 0 {
+		scale = 2
+	}
+
+	var in bytes.Buffer
+	if err := png.Encode(&in, enlarge(img, scale)); err != nil {
+		return nil, err
+	}
+	args := []string{"stdin", "stdout", "--psm", strconv.Itoa(psm)}
+	if t.Lang != "" {
+		args = append(args, "-l", t.Lang)
+	}
+	args = append(args, "tsv")
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = &in
+	var out, stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ocr: tesseract failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return parseTSV(out.String(), scale, img.Bounds().Min)
+}
+
+// parseTSV parses word entries of tesseract's TSV output, scaling boxes down by scale and moving them by origin.
+func parseTSV(tsv string, scale int, origin image.Point) ([]Word, error) {
+	words := []Word{}
+	for i, row := range strings.Split(tsv, "\n") {
+		if i == 0 || strings.TrimSpace(row) == "" {
+			continue
+		}
+		// level page_num block_num par_num line_num word_num left top width height conf text
+		cols := strings.SplitN(row, "\t", 12)
+		if len(cols) < 12 || cols[0] != "5" {
+			continue
+		}
+		text := strings.TrimSpace(cols[11])
+		if text == "" {
+			continue
+		}
+		nums := make([]int, 9)
+		for j := range nums {
+			n, err := strconv.Atoi(cols[j+1])
+			if err != nil {
+				return nil, fmt.Errorf("ocr: malformed tesseract output at line %d", i+1)
+			}
+			nums[j] = n
+		}
+		conf, err := strconv.ParseFloat(cols[10], 64)
+		if err != nil {
+			return nil, fmt.Errorf("ocr: malformed tesseract output at line %d", i+1)
+		}
+		block, par, line := nums[1], nums[2], nums[3]
+		left, top, width, height := nums[5], nums[6], nums[7], nums[8]
+		r := image.Rect(left/scale, top/scale, (left+width+scale-1)/scale, (top+height+scale-1)/scale)
+		words = append(words, Word{
+			Text:       text,
+			Rect:       r.Add(origin),
+			Confidence: conf / 100,
+			Line:       (block*1000+par)*1000 + line,
+		})
+	}
+	return words, nil
+}
+
+// enlarge scales img by an integer factor with nearest neighbour sampling, which keeps glyph edges sharp.
+func enlarge(img image.Image, scale int) image.Image {
+	if scale == 1 {
+		return img
+	}
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx()*scale, b.Dy()*scale))
+	for y := 0; y < dst.Rect.Dy(); y++ {
+		for x := 0; x < dst.Rect.Dx(); x++ {
+			dst.Set(x, y, img.At(b.Min.X+x/scale, b.Min.Y+y/scale))
+		}
+	}
+	return dst
+}
@@ -0,0 +1,71 @@
+package ocr
+
+import (
+	"image"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// tsv joins rows of tab separated columns, with the header tesseract writes first.
+func tsv(rows ...string) string {
+	header := "level\tpage_num\tblock_num\tpar_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext"
+	return strings.Join(append([]string{header}, rows...), "\n") + "\n"
+}
+
+func TestParseTSV(t *testing.T) {
+	out := tsv(
+		"1\t1\t0\t0\t0\t0\t0\t0\t400\t200\t-1\t",
+		"2\t1\t1\t0\t0\t0\t20\t10\t150\t30\t-1\t",
+		"3\t1\t1\t1\t0\t0\t20\t10\t150\t30\t-1\t",
+		"4\t1\t1\t1\t1\t0\t20\t10\t150\t30\t-1\t",
+		"5\t1\t1\t1\t1\t1\t20\t10\t60\t30\t96.541527\tSave",
+		"5\t1\t1\t1\t1\t2\t90\t11\t81\t29\t91\tAs...",
+		// Whitespace-only words are dropped.
+		"5\t1\t1\t1\t1\t3\t180\t10\t5\t30\t95\t ",
+		"5\t1\t2\t1\t1\t1\t21\t101\t40\t20\t48.5\tCancel",
+	)
+	got, err := parseTSV(out, 2, image.Pt(100, 50))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Word{
+		{Text: "Save", Rect: image.Rect(110, 55, 140, 70), Confidence: 0.96541527, Line: 1001001},
+		// Odd coordinates are rounded outwards.
+		{Text: "As...", Rect: image.Rect(145, 55, 186, 70), Confidence: 0.91, Line: 1001001},
+		{Text: "Cancel", Rect: image.Rect(110, 100, 131, 111), Confidence: 0.485, Line: 2001001},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parseTSV = %v, want %v", got, want)
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.Text != w.Text || g.Rect != w.Rect || g.Line != w.Line || math.Abs(g.Confidence-w.Confidence) > 1e-9 {
+			t.Errorf("word %d is %+v, want %+v", i, g, w)
+		}
+	}
+}
+
+func TestParseTSVNoWords(t *testing.T) {
+	for _, out := range []string{"", tsv(), tsv("1\t1\t0\t0\t0\t0\t0\t0\t400\t200\t-1\t")} {
+		got, err := parseTSV(out, 1, image.Point{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, []Word{}) {
+			t.Errorf("parseTSV(%q) = %v, want none", out, got)
+		}
+	}
+}
+
+func TestParseTSVMalformed(t *testing.T) {
+	for _, row := range []string{
+		"5\t1\t1\t1\t1\t1\tx\t10\t60\t30\t96\tSave",
+		"5\t1\t1\t1\t1\t1\t20\t10\t60\t30\thigh\tSave",
+	} {
+		if _, err := parseTSV(tsv(row), 1, image.Point{}); err == nil {
+			t.Errorf("parseTSV accepted %q", row)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package robot
+
+import (
+	"fmt"
+	"image"
+	"math"
+)
+
+// Frame is an area of the screen which positions can be relative to, such as
+// a display or a window. Its bounds are queried each time a Pos is resolved,
+// so positions follow a window when it moves. *app.Window and the client
+// area returned by app.Window.Client are frames.
+type Frame interface {
+	// Bounds returns the area in screen coordinates.
+	Bounds() (image.Rectangle, error)
+}
+
+// Anchor is a point of a frame given by fractions of its width and height, from the top-left corner.
+type Anchor struct {
+	X, Y float64
+}
+
+// Anchors at the corners, the middles of the edges, and the center of a
+// frame. Anchors on the right or bottom edge are at Max of the bounds, which
+// is just outside of them, so use an offset to point inside.
+var (
+	TopLeft     = Anchor{0, 0}
+	Top         = Anchor{0.5, 0}
+	TopRight    = Anchor{1, 0}
+	LeftCenter  = Anchor{0, 0.5}
+	Center      = Anchor{0.5, 0.5}
+	RightCenter = Anchor{1, 0.5}
+	BottomLeft  = Anchor{0, 1}
+	Bottom      = Anchor{0.5, 1}
+	BottomRight = Anchor{1, 1}
+)
+
+// Pos is a position which is resolved to a screen point when an action is
+// performed: the Anchor point of Frame moved by Offset. When Frame is nil,
+// Offset is the position in screen coordinates.
+type Pos struct {
+	Frame  Frame
+	Anchor Anchor
+	Offset image.Point
+}
+
+// At returns the position of the anchor of f moved by offset, e.g.
+// At(w, BottomRight, image.Pt(-20, -20)).
+func At(f Frame, anchor Anchor, offset image.Point) Pos {
+	return Pos{Frame: f, Anchor: anchor, Offset: offset}
+}
+
+// Resolve returns the position in screen coordinates.
+func (p Pos) Resolve() (image.Point, error) {
+	if p.Frame == nil {
+		return p.Offset, nil
+	}
+	r, err := p.Frame.Bounds()
+	if err != nil {
+		return image.Point{}, err
+	}
+	x := r.Min.X + int(math.Round(p.Anchor.X*float64(r.Dx())))
+	y := r.Min.Y + int(math.Round(p.Anchor.Y*float64(r.Dy())))
+	return image.Pt(x, y).Add(p.Offset), nil
+}
+
+// Rect is a fixed area of the screen as a Frame.
+type Rect image.Rectangle
+
+// Bounds returns r.
+func (r Rect) Bounds() (image.Rectangle, error) {
+	return image.Rectangle(r), nil
+}
+
+// Display is the n-th display returned by Displays as a Frame.
+type Display int
+
+// Bounds returns the bounds of the display.
+func (d Display) Bounds() (image.Rectangle, error) {
+	list, err := Displays()
+	if err != nil {
+		return image.Rectangle{}, err
+	}
+	if d < 0 || int(d) >= len(list) {
+		return image.Rectangle{}, fmt.Errorf("robot: display %d does not exist", int(d))
+	}
+	return list[d], nil
+}
+
+// MmvAt moves the mouse cursor to p, resolved at the time of the call.
+func MmvAt(p Pos) error {
+	pos, err := p.Resolve()
+	if err != nil {
+		return err
+	}
+	return Mmv(pos)
+}
+
+// BtnAt operates a mouse button at p, resolved at the time of the call.
+func BtnAt(button Button, operation Op, p Pos) error {
+	pos, err := p.Resolve()
+	if err != nil {
+		return err
+	}
+	return Btn(button, operation, pos)
+}
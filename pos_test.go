@@ -0,0 +1,85 @@
+package robot
+
+import (
+	"errors"
+	"image"
+	"testing"
+)
+
+// frame is a Frame whose bounds are changed by tests.
+type frame struct {
+	bounds image.Rectangle
+	err    error
+}
+
+func (f *frame) Bounds() (image.Rectangle, error) {
+	return f.bounds, f.err
+}
+
+func TestPosResolveAnchors(t *testing.T) {
+	r := Rect(image.Rect(100, 50, 301, 151))
+	tests := []struct {
+		anchor Anchor
+		want   image.Point
+	}{
+		{TopLeft, image.Pt(100, 50)},
+		// Halves of the odd width and height are rounded up.
+		{Top, image.Pt(201, 50)},
+		{TopRight, image.Pt(301, 50)},
+		{LeftCenter, image.Pt(100, 101)},
+		{Center, image.Pt(201, 101)},
+		{RightCenter, image.Pt(301, 101)},
+		{BottomLeft, image.Pt(100, 151)},
+		{Bottom, image.Pt(201, 151)},
+		{BottomRight, image.Pt(301, 151)},
+		// Fractions are rounded to the nearest pixel.
+		{Anchor{0.25, 0.1}, image.Pt(150, 60)},
+		{Anchor{1.0 / 3, 2.0 / 3}, image.Pt(167, 117)},
+		// Anchors may lie outside of the frame. Halves are rounded away from zero.
+		{Anchor{-0.5, 1.5}, image.Pt(-1, 202)},
+	}
+	for _, tt := range tests {
+		got, err := At(r, tt.anchor, image.Point{}).Resolve()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("anchor %v resolved to %v, want %v", tt.anchor, got, tt.want)
+		}
+	}
+}
+
+func TestPosResolveOffset(t *testing.T) {
+	p := At(Rect(image.Rect(100, 50, 300, 150)), BottomRight, image.Pt(-20, -10))
+	if got, err := p.Resolve(); err != nil || got != image.Pt(280, 140) {
+		t.Errorf("Resolve() = %v, %v, want (280,140)", got, err)
+	}
+	// Without a frame, the offset is in screen coordinates.
+	p = Pos{Anchor: Center, Offset: image.Pt(7, 9)}
+	if got, err := p.Resolve(); err != nil || got != image.Pt(7, 9) {
+		t.Errorf("Resolve() without a frame = %v, %v, want (7,9)", got, err)
+	}
+}
+
+func TestPosFollowsFrame(t *testing.T) {
+	f := &frame{bounds: image.Rect(0, 0, 100, 100)}
+	p := At(f, Center, image.Pt(1, 1))
+	if got, _ := p.Resolve(); got != image.Pt(51, 51) {
+		t.Errorf("Resolve() = %v, want (51,51)", got)
+	}
+	f.bounds = image.Rect(500, 400, 700, 500)
+	if got, _ := p.Resolve(); got != image.Pt(601, 451) {
+		t.Errorf("Resolve() after moving = %v, want (601,451)", got)
+	}
+
+	f.err = errors.New("window does not exist")
+	if _, err := p.Resolve(); err != f.err {
+		t.Errorf("Resolve() = %v, want the error of the frame", err)
+	}
+	if err := MmvAt(p); err != f.err {
+		t.Errorf("MmvAt = %v, want the error of the frame", err)
+	}
+	if err := BtnAt(Left, Click, p); err != f.err {
+		t.Errorf("BtnAt = %v, want the error of the frame", err)
+	}
+}
@@ -0,0 +1,29 @@
+package robot
+
+/*
+#cgo LDFLAGS: -lX11 -lXext
+#include <X11/Xlib.h>
+#include <X11/extensions/dpms.h>
+*/
+import "C"
+
+func pw(op PwOp) {
+	dpy, err := openDisplay()
+	if err != nil {
+		return
+	}
+	displayMutex.Lock()
+	defer displayMutex.Unlock()
+	if C.DPMSCapable(dpy) == 0 {
+		return
+	}
+	switch op {
+	case MonitorOn:
+		C.DPMSForceLevel(dpy, C.DPMSModeOn)
+	case MonitorOff:
+		// DPMS must be enabled for the level to take effect.
+		C.DPMSEnable(dpy)
+		C.DPMSForceLevel(dpy, C.DPMSModeOff)
+	}
+	C.XFlush(dpy)
+}
@@ -1,2 +1,2 @@
-// Package robot provides low-level functions for GUI automation such as moving/clicking mouse, typing keyboard etc (Support Windows and macOS).
+// Package robot provides low-level functions for GUI automation such as moving/clicking mouse, typing keyboard etc (Support Windows, macOS and Linux with X11).
 package robot
@@ -0,0 +1,31 @@
+package robot
+
+// #include <X11/Xlib.h>
+import "C"
+
+import (
+	"github.com/kbinani/robot/internal/x11"
+	"unsafe"
+)
+
+// displayMutex serializes requests sent through the shared connection. It is
+// shared with package app, which uses the same connection.
+var displayMutex = &x11.Mutex
+
+// openDisplay returns the connection to the X server named by $DISPLAY, shared by the packages.
+func openDisplay() (*C.Display, error) {
+	dpy, err := x11.Open()
+	return (*C.Display)(dpy), err
+}
+
+// takeXError returns the code of the last X protocol error and clears it. Call XSync before, to receive pending errors.
+func takeXError() int {
+	return x11.TakeError()
+}
+
+// clearXErrors drops the errors of requests sent so far. Call it before a
+// request whose error is checked, as other requests may have failed
+// unnoticed. Callers must hold displayMutex.
+func clearXErrors(dpy *C.Display) {
+	x11.ClearErrors(unsafe.Pointer(dpy))
+}
@@ -0,0 +1,33 @@
+package robot
+
+import (
+	"fmt"
+	"github.com/kbinani/robot/internal/xvfb"
+	"os"
+	"testing"
+)
+
+// xvfbErr is why tests needing an X server are skipped.
+var xvfbErr error
+
+func TestMain(m *testing.M) {
+	// A black root window, rather than the default pattern, keeps captured pixels predictable.
+	server, err := xvfb.Start(1280, 800, "-br")
+	if err == nil {
+		os.Setenv("DISPLAY", server.Display)
+	} else {
+		xvfbErr = fmt.Errorf("cannot start Xvfb: %v", err)
+	}
+	code := m.Run()
+	if server != nil {
+		server.Stop()
+	}
+	os.Exit(code)
+}
+
+// needX skips the test unless TestMain has started Xvfb.
+func needX(t *testing.T) {
+	if xvfbErr != nil {
+		t.Skip(xvfbErr)
+	}
+}
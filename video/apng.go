@@ -0,0 +1,136 @@
+package video
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image/png"
+	"io"
+	"time"
+)
+
+// EncodeAPNG writes frames as an animated PNG. Frames must have the same size.
+func EncodeAPNG(w io.Writer, frames []Frame, fps float64) error {
+	if len(frames) == 0 {
+		return errors.New("video: no frames")
+	}
+	size := frames[0].Image.Bounds().Size()
+	if _, err := io.WriteString(w, pngSignature); err != nil {
+		return err
+	}
+	var sequence uint32
+	var ihdr []byte
+	for i, f := range frames {
+		if f.Image.Bounds().Size() != size {
+			return errors.New("video: frames differ in size")
+		}
+		// Every frame shares the IHDR of the first one, so all must be encoded
+		// with the same colour type. An opaque *image.RGBA is always written as
+		// 8 bit truecolour without alpha.
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, flatten(f.Image)); err != nil {
+			return err
+		}
+		chunks, err := pngChunks(buf.Bytes())
+		if err != nil {
+			return err
+		}
+		if i > 0 && !bytes.Equal(chunks[0].data, ihdr) {
+			return errors.New("video: frames differ in PNG header")
+		}
+		if i == 0 {
+			ihdr = chunks[0].data
+			if err := writeChunk(w, "IHDR", chunks[0].data); err != nil {
+				return err
+			}
+			actl := make([]byte, 8)
+			binary.BigEndian.PutUint32(actl[0:], uint32(len(frames)))
+			binary.BigEndian.PutUint32(actl[4:], 0) // loop forever
+			if err := writeChunk(w, "acTL", actl); err != nil {
+				return err
+			}
+		}
+
+		delay := frameDelay(frames, i, fps)
+		fctl := make([]byte, 26)
+		binary.BigEndian.PutUint32(fctl[0:], sequence)
+		binary.BigEndian.PutUint32(fctl[4:], uint32(size.X))
+		binary.BigEndian.PutUint32(fctl[8:], uint32(size.Y))
+		ms := delay / time.Millisecond
+		if ms > 0xffff {
+			ms = 0xffff
+		}
+		binary.BigEndian.PutUint16(fctl[20:], uint16(ms))
+		binary.BigEndian.PutUint16(fctl[22:], 1000)
+		// x/y offsets, dispose_op and blend_op are zero: the frame replaces the whole canvas.
+		sequence++
+		if err := writeChunk(w, "fcTL", fctl); err != nil {
+			return err
+		}
+
+		for _, c := range chunks {
+			if c.name != "IDAT" {
+				continue
+			}
+			if i == 0 {
+				err = writeChunk(w, "IDAT", c.data)
+			} else {
+				fdat := make([]byte, 4+len(c.data))
+				binary.BigEndian.PutUint32(fdat, sequence)
+				copy(fdat[4:], c.data)
+				sequence++
+				err = writeChunk(w, "fdAT", fdat)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return writeChunk(w, "IEND", nil)
+}
+
+const pngSignature = "\x89PNG\r\n\x1a\n"
+
+type pngChunk struct {
+	name string
+	data []byte
+}
+
+// pngChunks splits an encoded PNG file into its chunks.
+func pngChunks(b []byte) ([]pngChunk, error) {
+	if !bytes.HasPrefix(b, []byte(pngSignature)) {
+		return nil, errors.New("video: invalid PNG signature")
+	}
+	b = b[len(pngSignature):]
+	chunks := []pngChunk{}
+	for len(b) >= 12 {
+		n := int(binary.BigEndian.Uint32(b))
+		if len(b) < 12+n {
+			return nil, errors.New("video: truncated PNG chunk")
+		}
+		chunks = append(chunks, pngChunk{name: string(b[4:8]), data: b[8 : 8+n]})
+		b = b[12+n:]
+	}
+	if len(chunks) == 0 || chunks[0].name != "IHDR" {
+		return nil, errors.New("video: PNG does not start with IHDR")
+	}
+	return chunks, nil
+}
+
+func writeChunk(w io.Writer, name string, data []byte) error {
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint32(header, uint32(len(data)))
+	copy(header[4:], name)
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:])
+	crc.Write(data)
+	footer := make([]byte, 4)
+	binary.BigEndian.PutUint32(footer, crc.Sum32())
+	for _, b := range [][]byte{header, data, footer} {
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
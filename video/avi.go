@@ -0,0 +1,119 @@
+package video
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image/jpeg"
+	"io"
+	"math"
+)
+
+// EncodeAVI writes frames as Motion JPEG in an AVI container. Frames must
+// have the same size. AVI has a constant frame rate, which is fps.
+func EncodeAVI(w io.Writer, frames []Frame, fps float64) error {
+	if len(frames) == 0 {
+		return errors.New("video: no frames")
+	}
+	size := frames[0].Image.Bounds().Size()
+	images := [][]byte{}
+	largest := 0
+	for _, f := range frames {
+		if f.Image.Bounds().Size() != size {
+			return errors.New("video: frames differ in size")
+		}
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, f.Image, &jpeg.Options{Quality: 85}); err != nil {
+			return err
+		}
+		if buf.Len()%2 == 1 {
+			buf.WriteByte(0)
+		}
+		images = append(images, buf.Bytes())
+		if buf.Len() > largest {
+			largest = buf.Len()
+		}
+	}
+
+	var movi, index bytes.Buffer
+	movi.WriteString("movi")
+	for _, img := range images {
+		// Offsets in idx1 are relative to the "movi" identifier.
+		le(&index, []byte("00dc"), uint32(0x10), uint32(movi.Len()), uint32(len(img)))
+		le(&movi, []byte("00dc"), uint32(len(img)), img)
+	}
+
+	rate := uint32(math.Round(fps * 1000))
+	count := uint32(len(images))
+	width, height := uint32(size.X), uint32(size.Y)
+
+	var avih bytes.Buffer
+	le(&avih,
+		uint32(math.Round(1e6/fps)),  // dwMicroSecPerFrame
+		uint32(float64(largest)*fps), // dwMaxBytesPerSec
+		uint32(0),                    // dwPaddingGranularity
+		uint32(0x10),                 // dwFlags: AVIF_HASINDEX
+		count,                        // dwTotalFrames
+		uint32(0),                    // dwInitialFrames
+		uint32(1),                    // dwStreams
+		uint32(largest),              // dwSuggestedBufferSize
+		width, height,                // dwWidth, dwHeight
+		[4]uint32{}, // dwReserved
+	)
+	var strh bytes.Buffer
+	le(&strh,
+		[]byte("vids"), []byte("MJPG"), // fccType, fccHandler
+		uint32(0),            // dwFlags
+		uint16(0), uint16(0), // wPriority, wLanguage
+		uint32(0),          // dwInitialFrames
+		uint32(1000), rate, // dwScale, dwRate
+		uint32(0), count, // dwStart, dwLength
+		uint32(largest), // dwSuggestedBufferSize
+		int32(-1),       // dwQuality
+		uint32(0),       // dwSampleSize
+		[4]int16{0, 0, int16(size.X), int16(size.Y)}, // rcFrame
+	)
+	var strf bytes.Buffer
+	le(&strf,
+		uint32(40), int32(width), int32(height), // biSize, biWidth, biHeight
+		uint16(1), uint16(24), // biPlanes, biBitCount
+		[]byte("MJPG"), // biCompression
+		width*height*3, // biSizeImage
+		int32(0), int32(0), uint32(0), uint32(0),
+	)
+
+	strl := list("strl", chunk("strh", strh.Bytes()), chunk("strf", strf.Bytes()))
+	hdrl := list("hdrl", chunk("avih", avih.Bytes()), strl)
+	riff := list("AVI ", hdrl, chunk("LIST", movi.Bytes()), chunk("idx1", index.Bytes()))
+	riff = append([]byte("RIFF"), riff[4:]...)
+	_, err := w.Write(riff)
+	return err
+}
+
+// le writes values in little endian. Byte slices are written as is.
+func le(buf *bytes.Buffer, values ...interface{}) {
+	for _, v := range values {
+		if b, ok := v.([]byte); ok {
+			buf.Write(b)
+			continue
+		}
+		binary.Write(buf, binary.LittleEndian, v)
+	}
+}
+
+func chunk(id string, data []byte) []byte {
+	var buf bytes.Buffer
+	le(&buf, []byte(id), uint32(len(data)), data)
+	if len(data)%2 == 1 {
+		buf.WriteByte(0)
+	}
+	return buf.Bytes()
+}
+
+func list(kind string, children ...[]byte) []byte {
+	data := []byte(kind)
+	for _, c := range children {
+		data = append(data, c...)
+	}
+	return chunk("LIST", data)
+}
@@ -0,0 +1,101 @@
+package video
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"testing"
+	"time"
+)
+
+// offsetFrames returns frames of a region left of and above the origin, as
+// recorded on a display arranged there. The second frame has a transparent
+// area outside of displays.
+func offsetFrames() []Frame {
+	r := image.Rect(-100, -50, -60, -20)
+	start := time.Now()
+	frames := []Frame{}
+	for i := 0; i < 2; i++ {
+		img := image.NewRGBA(r)
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				img.Set(x, y, color.RGBA{0xff, 0xff, 0xff, 0xff})
+			}
+		}
+		if i == 1 {
+			img.Set(r.Min.X, r.Min.Y, color.RGBA{})
+		}
+		frames = append(frames, Frame{Time: start.Add(time.Duration(i) * 100 * time.Millisecond), Image: img})
+	}
+	return frames
+}
+
+func TestEncodeGIFOffsetRegion(t *testing.T) {
+	var buf bytes.Buffer
+	if err := EncodeGIF(&buf, offsetFrames(), 10); err != nil {
+		t.Fatal(err)
+	}
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Image) != 2 {
+		t.Fatalf("got %d frames, want 2", len(anim.Image))
+	}
+	want := image.Rect(0, 0, 40, 30)
+	for i, p := range anim.Image {
+		if p.Bounds() != want {
+			t.Errorf("frame %d: bounds are %v, want %v", i, p.Bounds(), want)
+		}
+	}
+	if anim.Delay[0] != 10 {
+		t.Errorf("delay is %d, want 10", anim.Delay[0])
+	}
+	if r, g, b, _ := anim.Image[1].At(0, 0).RGBA(); r|g|b != 0 {
+		t.Errorf("transparent pixel is not black")
+	}
+	if r, _, _, _ := anim.Image[1].At(1, 0).RGBA(); r != 0xffff {
+		t.Errorf("white pixel is not white")
+	}
+}
+
+func TestEncodeAPNGMixedAlpha(t *testing.T) {
+	var buf bytes.Buffer
+	if err := EncodeAPNG(&buf, offsetFrames(), 10); err != nil {
+		t.Fatal(err)
+	}
+	chunks, err := pngChunks(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, c := range chunks {
+		names = append(names, c.name)
+	}
+	want := "[IHDR acTL fcTL IDAT fcTL fdAT IEND]"
+	if got := fmt.Sprint(names); got != want {
+		t.Errorf("chunks are %s, want %s", got, want)
+	}
+	// The default image of an APNG is its first frame, which any PNG decoder reads.
+	img, err := png.Decode(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds() != image.Rect(0, 0, 40, 30) {
+		t.Errorf("bounds are %v", img.Bounds())
+	}
+}
+
+func TestEncodeFramesDifferInSize(t *testing.T) {
+	frames := offsetFrames()
+	frames[1].Image = image.NewRGBA(image.Rect(0, 0, 10, 10))
+	if err := EncodeGIF(&bytes.Buffer{}, frames, 10); err == nil {
+		t.Error("EncodeGIF accepted frames of different sizes")
+	}
+	if err := EncodeAPNG(&bytes.Buffer{}, frames, 10); err == nil {
+		t.Error("EncodeAPNG accepted frames of different sizes")
+	}
+}
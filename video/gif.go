@@ -0,0 +1,51 @@
+package video
+
+import (
+	"errors"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"io"
+	"time"
+)
+
+// EncodeGIF writes frames as an animated GIF, using the web-safe palette. Frames must have the same size.
+func EncodeGIF(w io.Writer, frames []Frame, fps float64) error {
+	if len(frames) == 0 {
+		return errors.New("video: no frames")
+	}
+	size := frames[0].Image.Bounds().Size()
+	anim := &gif.GIF{}
+	for i, f := range frames {
+		b := f.Image.Bounds()
+		if b.Size() != size {
+			return errors.New("video: frames differ in size")
+		}
+		// Frames are placed at the origin of the GIF, wherever the recorded region was.
+		p := image.NewPaletted(image.Rect(0, 0, size.X, size.Y), palette.WebSafe)
+		draw.Draw(p, p.Bounds(), flatten(f.Image), image.Point{}, draw.Src)
+		anim.Image = append(anim.Image, p)
+		// GIF delays are in 1/100 seconds.
+		anim.Delay = append(anim.Delay, int(frameDelay(frames, i, fps)/(10*time.Millisecond)))
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// frameDelay returns how long the i-th frame is displayed.
+func frameDelay(frames []Frame, i int, fps float64) time.Duration {
+	if i+1 < len(frames) {
+		return frames[i+1].Time.Sub(frames[i].Time)
+	}
+	return time.Duration(float64(time.Second) / fps)
+}
+
+// flatten returns img moved to the origin and drawn over black, so that pixels
+// outside of displays, which Capture leaves transparent, become black.
+func flatten(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), image.Black, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Over)
+	return dst
+}
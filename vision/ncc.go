@@ -0,0 +1,224 @@
+package vision
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// planes holds an image as floating point channels, which is either luminance alone or red, green and blue.
+type planes struct {
+	w, h int
+	ch   [][]float32
+	// mask tells which pixels take part in matching. It is nil when every pixel does.
+	mask  []bool
+	valid int
+	// sum and sumSq are the sums of valid samples of all channels, and of their squares.
+	sum, sumSq float64
+}
+
+func newPlanes(img image.Image, mode Mode) *planes {
+	b := img.Bounds()
+	p := &planes{w: b.Dx(), h: b.Dy()}
+	count := 1
+	if mode == Color {
+		count = 3
+	}
+	for i := 0; i < count; i++ {
+		p.ch = append(p.ch, make([]float32, p.w*p.h))
+	}
+	mask := make([]bool, p.w*p.h)
+	opaque := true
+	for y := 0; y < p.h; y++ {
+		for x := 0; x < p.w; x++ {
+			i := y*p.w + x
+			c := color.NRGBAModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.NRGBA)
+			mask[i] = c.A >= 0x80
+			opaque = opaque && mask[i]
+			if mode == Color {
+				p.ch[0][i] = float32(c.R)
+				p.ch[1][i] = float32(c.G)
+				p.ch[2][i] = float32(c.B)
+			} else {
+				p.ch[0][i] = 0.299*float32(c.R) + 0.587*float32(c.G) + 0.114*float32(c.B)
+			}
+		}
+	}
+	if !opaque {
+		p.mask = mask
+	}
+	p.updateSums()
+	return p
+}
+
+func (p *planes) isValid(i int) bool {
+	return p.mask == nil || p.mask[i]
+}
+
+func (p *planes) updateSums() {
+	p.valid, p.sum, p.sumSq = 0, 0, 0
+	for i := 0; i < p.w*p.h; i++ {
+		if !p.isValid(i) {
+			continue
+		}
+		p.valid++
+		for _, c := range p.ch {
+			v := float64(c[i])
+			p.sum += v
+			p.sumSq += v * v
+		}
+	}
+}
+
+// half returns p downsampled by 2 in both directions. A pixel is valid when
+// at least half of its source pixels are, and its value is their average.
+func (p *planes) half() *planes {
+	q := &planes{w: p.w / 2, h: p.h / 2}
+	for range p.ch {
+		q.ch = append(q.ch, make([]float32, q.w*q.h))
+	}
+	if p.mask != nil {
+		q.mask = make([]bool, q.w*q.h)
+	}
+	for y := 0; y < q.h; y++ {
+		for x := 0; x < q.w; x++ {
+			src := []int{2*y*p.w + 2*x, 2*y*p.w + 2*x + 1, (2*y+1)*p.w + 2*x, (2*y+1)*p.w + 2*x + 1}
+			n := 0
+			for _, i := range src {
+				if p.isValid(i) {
+					n++
+				}
+			}
+			i := y*q.w + x
+			if q.mask != nil {
+				q.mask[i] = n >= 2
+			}
+			if n == 0 {
+				continue
+			}
+			for c := range p.ch {
+				var sum float32
+				for _, j := range src {
+					if p.isValid(j) {
+						sum += p.ch[c][j]
+					}
+				}
+				q.ch[c][i] = sum / float32(n)
+			}
+		}
+	}
+	q.updateSums()
+	return q
+}
+
+// score returns the normalized cross-correlation of needle and the area of h at (x, y).
+func score(h, needle *planes, x, y int) float64 {
+	var sI, sII, sIT float64
+	for ny := 0; ny < needle.h; ny++ {
+		row := (y+ny)*h.w + x
+		for nx := 0; nx < needle.w; nx++ {
+			j := ny*needle.w + nx
+			if !needle.isValid(j) {
+				continue
+			}
+			for c := range needle.ch {
+				v := float64(h.ch[c][row+nx])
+				t := float64(needle.ch[c][j])
+				sI += v
+				sII += v * v
+				sIT += v * t
+			}
+		}
+	}
+	n := float64(needle.valid * len(needle.ch))
+	varT := needle.sumSq - needle.sum*needle.sum/n
+	varI := sII - sI*sI/n
+	const eps = 1e-6
+	if varT < eps*n {
+		// Correlation is undefined for a uniform needle, so compare the mean instead.
+		if varI >= eps*n {
+			return 0
+		}
+		return 1 - math.Abs(sI-needle.sum)/n/255
+	}
+	if varI < eps*n {
+		return 0
+	}
+	return (sIT - sI*needle.sum/n) / math.Sqrt(varT*varI)
+}
+
+// coarseThresholdDrop lowers the threshold at the top level of the pyramid.
+// Downsampling averages away detail and the true position of a match falls
+// between coarse pixels, so a match scoring 0.9 at full resolution often
+// scores only 0.7 to 0.8 there. Candidates are verified at full resolution,
+// so a lower threshold only costs some refinement work.
+const coarseThresholdDrop = 0.2
+
+// search returns matches of needle in h, in the coordinates of h, using a coarse to fine search over an image pyramid.
+func search(h, needle *planes, opts Options) []Match {
+	levels := opts.Levels
+	if levels <= 0 {
+		levels = 1
+		size := needle.w
+		if needle.h < size {
+			size = needle.h
+		}
+		for levels < 4 && size>>levels >= 8 {
+			levels++
+		}
+	}
+	hs, ns := []*planes{h}, []*planes{needle}
+	for len(hs) < levels {
+		nextNeedle := ns[len(ns)-1].half()
+		if nextNeedle.w < 2 || nextNeedle.h < 2 || nextNeedle.valid == 0 {
+			break
+		}
+		hs = append(hs, hs[len(hs)-1].half())
+		ns = append(ns, nextNeedle)
+	}
+	top := len(hs) - 1
+
+	threshold := opts.Threshold
+	if top > 0 {
+		threshold -= coarseThresholdDrop
+	}
+	candidates := []Match{}
+	th, tn := hs[top], ns[top]
+	for y := 0; y <= th.h-tn.h; y++ {
+		for x := 0; x <= th.w-tn.w; x++ {
+			if s := score(th, tn, x, y); s >= threshold {
+				candidates = append(candidates, Match{Rect: image.Rect(x, y, x+tn.w, y+tn.h), Score: s})
+			}
+		}
+	}
+	candidates = suppress(candidates, 0.5)
+
+	matches := []Match{}
+	for _, c := range candidates {
+		pos := c.Rect.Min
+		s := c.Score
+		for l := top - 1; l >= 0; l-- {
+			pos, s = refine(hs[l], ns[l], pos.Mul(2), 2)
+		}
+		if s >= opts.Threshold {
+			matches = append(matches, Match{Rect: image.Rect(pos.X, pos.Y, pos.X+needle.w, pos.Y+needle.h), Score: s})
+		}
+	}
+	return matches
+}
+
+// refine returns the best position within radius of pos, and its score.
+func refine(h, needle *planes, pos image.Point, radius int) (image.Point, float64) {
+	best, bestScore := pos, math.Inf(-1)
+	for y := pos.Y - radius; y <= pos.Y+radius; y++ {
+		for x := pos.X - radius; x <= pos.X+radius; x++ {
+			if x < 0 || y < 0 || x > h.w-needle.w || y > h.h-needle.h {
+				continue
+			}
+			if s := score(h, needle, x, y); s > bestScore {
+				best, bestScore = image.Pt(x, y), s
+			}
+		}
+	}
+	return best, bestScore
+}
@@ -0,0 +1,121 @@
+// Package vision locates UI elements on screen by their appearance, for apps which cannot be inspected through accessibility APIs.
+package vision
+
+import (
+	"errors"
+	"github.com/kbinani/robot"
+	"image"
+	"sort"
+)
+
+// Mode represents how pixels are compared.
+type Mode int
+
+// Comparison modes.
+const (
+	// Gray compares luminance only. It is faster, and tolerates slight color changes.
+	Gray Mode = iota
+	// Color compares red, green and blue channels.
+	Color
+)
+
+// Options controls template matching.
+type Options struct {
+	Mode Mode
+	// Threshold is the lowest score of matches, from -1 to 1. Default is 0.9.
+	// Zero selects the default, so use a small value such as 1e-9 to accept
+	// every positively correlated area, or -1 to accept any area.
+	Threshold float64
+	// MaxResults limits the number of matches. Zero means no limit.
+	MaxResults int
+	// Overlap is the largest fraction of a match's area that may be covered
+	// by a better match. Worse matches overlapping more are suppressed.
+	// Default is 0.3.
+	Overlap float64
+	// Levels is the number of levels of the image pyramid. Zero chooses it
+	// from the size of the needle, and 1 disables the pyramid search.
+	Levels int
+}
+
+// Match is a location of the needle.
+type Match struct {
+	// Rect is where the needle was found, in the coordinates of the searched image, i.e. screen coordinates for Find.
+	Rect image.Rectangle
+	// Score is the normalized cross-correlation of the needle and the area, 1 being a perfect match.
+	Score float64
+}
+
+// Center returns the center of the match, which can be passed to robot.Btn or robot.Mmv.
+func (m Match) Center() image.Point {
+	return image.Pt((m.Rect.Min.X+m.Rect.Max.X)/2, (m.Rect.Min.Y+m.Rect.Max.Y)/2)
+}
+
+// Find captures region of the screen and returns where needle appears in it, best match first.
+// Transparent pixels of needle are ignored.
+func Find(needle image.Image, region image.Rectangle, opts Options) ([]Match, error) {
+	haystack, err := robot.Capture(region)
+	if err != nil {
+		return nil, err
+	}
+	return FindIn(haystack, needle, opts)
+}
+
+// FindIn is like Find, but searches haystack instead of the screen.
+func FindIn(haystack, needle image.Image, opts Options) ([]Match, error) {
+	opts = opts.withDefaults()
+	hb, nb := haystack.Bounds(), needle.Bounds()
+	if nb.Empty() {
+		return nil, errors.New("vision: needle is empty")
+	}
+	if nb.Dx() > hb.Dx() || nb.Dy() > hb.Dy() {
+		return []Match{}, nil
+	}
+	h := newPlanes(haystack, opts.Mode)
+	n := newPlanes(needle, opts.Mode)
+	if n.valid == 0 {
+		return nil, errors.New("vision: needle is fully transparent")
+	}
+
+	matches := search(h, n, opts)
+	for i := range matches {
+		matches[i].Rect = matches[i].Rect.Add(hb.Min)
+	}
+	matches = suppress(matches, opts.Overlap)
+	if opts.MaxResults > 0 && len(matches) > opts.MaxResults {
+		matches = matches[:opts.MaxResults]
+	}
+	return matches, nil
+}
+
+func (opts Options) withDefaults() Options {
+	if opts.Threshold == 0 {
+		opts.Threshold = 0.9
+	}
+	if opts.Overlap <= 0 {
+		opts.Overlap = 0.3
+	}
+	return opts
+}
+
+// suppress sorts matches by score and removes those overlapping a better match.
+func suppress(matches []Match, overlap float64) []Match {
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	result := []Match{}
+	for _, m := range matches {
+		area := float64(m.Rect.Dx() * m.Rect.Dy())
+		keep := true
+		for _, better := range result {
+			common := m.Rect.Intersect(better.Rect)
+			if float64(common.Dx()*common.Dy()) > overlap*area {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			result = append(result, m)
+		}
+	}
+	return result
+}
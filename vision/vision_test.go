@@ -0,0 +1,169 @@
+package vision
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math/rand"
+	"testing"
+)
+
+// noise returns an image of random blocks of size block, which has a single good match for any part of it.
+func noise(w, h, block int, seed int64) *image.RGBA {
+	r := rand.New(rand.NewSource(seed))
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y += block {
+		for x := 0; x < w; x += block {
+			c := color.RGBA{uint8(r.Intn(256)), uint8(r.Intn(256)), uint8(r.Intn(256)), 0xff}
+			draw.Draw(img, image.Rect(x, y, x+block, y+block), image.NewUniform(c), image.Point{}, draw.Src)
+		}
+	}
+	return img
+}
+
+func crop(img image.Image, r image.Rectangle) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, r.Min, draw.Src)
+	return dst
+}
+
+func TestFindInKnownLocation(t *testing.T) {
+	// Coarse pyramid levels average away detail smaller than a few pixels, like in real UIs.
+	haystack := noise(200, 150, 6, 1)
+	want := image.Rect(70, 45, 118, 81)
+	needle := crop(haystack, want)
+	for _, tt := range []struct {
+		name string
+		opts Options
+	}{
+		{"gray", Options{Levels: 1}},
+		{"color", Options{Mode: Color, Levels: 1}},
+		{"pyramid", Options{}},
+		{"pyramid color", Options{Mode: Color, Levels: 3}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := FindIn(haystack, needle, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(matches) != 1 {
+				t.Fatalf("got %d matches, want 1: %v", len(matches), matches)
+			}
+			if matches[0].Rect != want {
+				t.Errorf("got %v, want %v", matches[0].Rect, want)
+			}
+			if matches[0].Score < 0.999 {
+				t.Errorf("score of exact match is %v", matches[0].Score)
+			}
+		})
+	}
+}
+
+func TestFindInOffsetHaystack(t *testing.T) {
+	haystack := noise(140, 110, 2, 2)
+	shifted := image.NewRGBA(image.Rect(-20, -10, 120, 100))
+	draw.Draw(shifted, shifted.Bounds(), haystack, image.Point{}, draw.Src)
+
+	needle := crop(haystack, image.Rect(50, 30, 74, 54))
+	matches, err := FindIn(shifted, needle, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) == 0 {
+		t.Fatal("no match")
+	}
+	if want := image.Rect(30, 20, 54, 44); matches[0].Rect != want {
+		t.Errorf("got %v, want %v", matches[0].Rect, want)
+	}
+}
+
+func TestFindInMultiple(t *testing.T) {
+	haystack := image.NewRGBA(image.Rect(0, 0, 160, 120))
+	draw.Draw(haystack, haystack.Bounds(), image.White, image.Point{}, draw.Src)
+	needle := noise(20, 20, 2, 3)
+	at := []image.Point{{10, 10}, {100, 30}, {40, 90}}
+	for _, p := range at {
+		draw.Draw(haystack, needle.Bounds().Add(p), needle, image.Point{}, draw.Src)
+	}
+
+	matches, err := FindIn(haystack, needle, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != len(at) {
+		t.Fatalf("got %d matches, want %d: %v", len(matches), len(at), matches)
+	}
+	found := map[image.Point]bool{}
+	for _, m := range matches {
+		found[m.Rect.Min] = true
+	}
+	for _, p := range at {
+		if !found[p] {
+			t.Errorf("no match at %v", p)
+		}
+	}
+
+	matches, err = FindIn(haystack, needle, Options{MaxResults: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 2 {
+		t.Errorf("MaxResults: got %d matches, want 2", len(matches))
+	}
+}
+
+func TestFindInTransparentNeedle(t *testing.T) {
+	haystack := noise(120, 100, 2, 4)
+	want := image.Rect(40, 30, 64, 54)
+	needle := crop(haystack, want)
+	// Transparent pixels in the middle must match whatever is behind them.
+	for y := 8; y < 16; y++ {
+		for x := 8; x < 16; x++ {
+			needle.Set(x, y, color.RGBA{0xff, 0, 0xff, 0})
+		}
+	}
+	matches, err := FindIn(haystack, needle, Options{Levels: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) == 0 || matches[0].Rect != want {
+		t.Errorf("got %v, want a match at %v", matches, want)
+	}
+
+	if _, err := FindIn(haystack, image.NewRGBA(image.Rect(0, 0, 4, 4)), Options{}); err == nil {
+		t.Error("fully transparent needle did not fail")
+	}
+}
+
+func TestFindInNoMatch(t *testing.T) {
+	haystack := noise(100, 100, 2, 5)
+	needle := noise(16, 16, 2, 6)
+	matches, err := FindIn(haystack, needle, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("got %v, want no match", matches)
+	}
+
+	big := noise(120, 10, 2, 7)
+	matches, err = FindIn(haystack, big, Options{})
+	if err != nil || len(matches) != 0 {
+		t.Errorf("needle larger than haystack: got %v, %v", matches, err)
+	}
+}
+
+func TestHalf(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x++ {
+		img.Set(x, 0, color.RGBA{uint8(x * 10), uint8(x * 10), uint8(x * 10), 0xff})
+		img.Set(x, 1, color.RGBA{uint8(x * 10), uint8(x * 10), uint8(x * 10), 0xff})
+	}
+	p := newPlanes(img, Color).half()
+	if p.w != 2 || p.h != 1 {
+		t.Fatalf("got %dx%d, want 2x1", p.w, p.h)
+	}
+	if p.ch[0][0] != 5 || p.ch[0][1] != 25 {
+		t.Errorf("got %v, want [5 25]", p.ch[0])
+	}
+}
@@ -0,0 +1,51 @@
+package vision
+
+import (
+	"context"
+	"github.com/kbinani/robot"
+	"image"
+)
+
+// WaitForImage blocks until needle appears in region, and returns the best match.
+// When ctx is done first, a *robot.TimeoutError carrying the last screenshot is returned.
+func WaitForImage(ctx context.Context, needle image.Image, region image.Rectangle, opts Options) (Match, error) {
+	var last *image.RGBA
+	var found Match
+	err := robot.DefaultBackoff().Wait(ctx, func() (bool, error) {
+		matches, err := findOnce(needle, region, opts, &last)
+		if err != nil || len(matches) == 0 {
+			return false, err
+		}
+		found = matches[0]
+		return true, nil
+	})
+	if err != nil && err == ctx.Err() {
+		return Match{}, &robot.TimeoutError{What: "image to appear", Last: last, Err: err}
+	}
+	return found, err
+}
+
+// WaitForImageGone blocks until needle no longer appears in region.
+// When ctx is done first, a *robot.TimeoutError carrying the last screenshot is returned.
+func WaitForImageGone(ctx context.Context, needle image.Image, region image.Rectangle, opts Options) error {
+	var last *image.RGBA
+	err := robot.DefaultBackoff().Wait(ctx, func() (bool, error) {
+		matches, err := findOnce(needle, region, opts, &last)
+		return len(matches) == 0, err
+	})
+	if err != nil && err == ctx.Err() {
+		return &robot.TimeoutError{What: "image to disappear", Last: last, Err: err}
+	}
+	return err
+}
+
+// findOnce captures region, stores the screenshot to last, and searches needle in it.
+func findOnce(needle image.Image, region image.Rectangle, opts Options, last **image.RGBA) ([]Match, error) {
+	img, err := robot.Capture(region)
+	if err != nil {
+		return nil, err
+	}
+	*last = img
+	opts.MaxResults = 1
+	return FindIn(img, needle, opts)
+}
@@ -0,0 +1,125 @@
+package visualtest
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+)
+
+// Metric represents how pixels are compared.
+type Metric int
+
+// Comparison metrics.
+const (
+	// Channel treats pixels as equal when each of red, green and blue differ by at most Options.Tolerance.
+	Channel Metric = iota
+	// DeltaE treats pixels as equal when their CIE76 color difference in
+	// L*a*b* space is at most Options.DeltaE. It follows how different colors
+	// look to humans, e.g. a difference in dark blue is less visible than in
+	// green.
+	DeltaE
+)
+
+// Result is the outcome of Compare.
+type Result struct {
+	// DiffPixels is the number of differing pixels outside of ignored areas.
+	DiffPixels int
+	// Pixels is the number of compared pixels.
+	Pixels int
+	// MaxDeltaE is the largest color difference found, in CIE76.
+	MaxDeltaE float64
+	// Diff shows the expected image faded, with differing pixels in red and ignored areas in blue.
+	Diff *image.RGBA
+}
+
+// Compare compares actual with expected, which must have the same size.
+// Both are compared relative to their top-left corners.
+func Compare(actual, expected image.Image, opts Options) (Result, error) {
+	ab, eb := actual.Bounds(), expected.Bounds()
+	if ab.Size() != eb.Size() {
+		return Result{}, errors.New("visualtest: size differs")
+	}
+	opts = opts.withDefaults()
+	diff := image.NewRGBA(image.Rectangle{Max: ab.Size()})
+	var result Result
+	for y := 0; y < ab.Dy(); y++ {
+		for x := 0; x < ab.Dx(); x++ {
+			a := color.NRGBAModel.Convert(actual.At(ab.Min.X+x, ab.Min.Y+y)).(color.NRGBA)
+			e := color.NRGBAModel.Convert(expected.At(eb.Min.X+x, eb.Min.Y+y)).(color.NRGBA)
+			gray := uint8(0xc0 + (uint16(e.R)+uint16(e.G)+uint16(e.B))/12)
+			if ignored(image.Pt(x, y), opts.Ignore) {
+				diff.SetRGBA(x, y, color.RGBA{gray / 2, gray / 2, 0xff, 0xff})
+				continue
+			}
+			result.Pixels++
+			d := deltaE(a, e)
+			if d > result.MaxDeltaE {
+				result.MaxDeltaE = d
+			}
+			var differs bool
+			if opts.Metric == DeltaE {
+				differs = d > opts.DeltaE
+			} else {
+				differs = channelDiff(a.R, e.R) > opts.Tolerance ||
+					channelDiff(a.G, e.G) > opts.Tolerance ||
+					channelDiff(a.B, e.B) > opts.Tolerance ||
+					channelDiff(a.A, e.A) > opts.Tolerance
+			}
+			if differs {
+				result.DiffPixels++
+				diff.SetRGBA(x, y, color.RGBA{0xff, 0, 0, 0xff})
+			} else {
+				diff.SetRGBA(x, y, color.RGBA{gray, gray, gray, 0xff})
+			}
+		}
+	}
+	result.Diff = diff
+	return result, nil
+}
+
+func ignored(p image.Point, areas []image.Rectangle) bool {
+	for _, r := range areas {
+		if p.In(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func channelDiff(a, b uint8) int {
+	if a > b {
+		return int(a - b)
+	}
+	return int(b - a)
+}
+
+// deltaE returns the CIE76 difference of a and b.
+func deltaE(a, b color.NRGBA) float64 {
+	l1, a1, b1 := lab(a)
+	l2, a2, b2 := lab(b)
+	return math.Sqrt((l1-l2)*(l1-l2) + (a1-a2)*(a1-a2) + (b1-b2)*(b1-b2))
+}
+
+// lab converts an sRGB color to CIE L*a*b* under the D65 white point.
+func lab(c color.NRGBA) (l, a, b float64) {
+	linear := func(v uint8) float64 {
+		f := float64(v) / 255
+		if f <= 0.04045 {
+			return f / 12.92
+		}
+		return math.Pow((f+0.055)/1.055, 2.4)
+	}
+	r, g, bl := linear(c.R), linear(c.G), linear(c.B)
+	x := (0.4124*r + 0.3576*g + 0.1805*bl) / 0.95047
+	y := 0.2126*r + 0.7152*g + 0.0722*bl
+	z := (0.0193*r + 0.1192*g + 0.9505*bl) / 1.08883
+	f := func(t float64) float64 {
+		if t > 216.0/24389 {
+			return math.Cbrt(t)
+		}
+		return (24389.0/27*t + 16) / 116
+	}
+	fx, fy, fz := f(x), f(y), f(z)
+	return 116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)
+}
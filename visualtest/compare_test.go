@@ -0,0 +1,139 @@
+package visualtest
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"testing"
+)
+
+var (
+	red  = color.RGBA{0xff, 0, 0, 0xff}
+	blue = color.RGBA{0, 0, 0xff, 0xff}
+)
+
+func uniform(r image.Rectangle, c color.Color) *image.RGBA {
+	img := image.NewRGBA(r)
+	draw.Draw(img, r, image.NewUniform(c), image.Point{}, draw.Src)
+	return img
+}
+
+func TestCompareIdentical(t *testing.T) {
+	img := uniform(image.Rect(0, 0, 8, 6), color.RGBA{0x20, 0x40, 0x60, 0xff})
+	result, err := Compare(img, img, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.DiffPixels != 0 || result.Pixels != 48 || result.MaxDeltaE != 0 {
+		t.Errorf("result is %+v, want 48 equal pixels", result)
+	}
+	if result.Diff.Bounds() != image.Rect(0, 0, 8, 6) {
+		t.Errorf("diff bounds are %v", result.Diff.Bounds())
+	}
+}
+
+func TestCompareDiffPixels(t *testing.T) {
+	// The images are compared relative to their top-left corners.
+	expected := uniform(image.Rect(0, 0, 10, 10), color.White)
+	actual := uniform(image.Rect(100, 200, 110, 210), color.White)
+	differing := []image.Point{{0, 0}, {3, 4}, {9, 9}}
+	for _, p := range differing {
+		actual.Set(100+p.X, 200+p.Y, blue)
+	}
+	result, err := Compare(actual, expected, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.DiffPixels != len(differing) || result.Pixels != 100 {
+		t.Errorf("%d of %d pixels differ, want %d of 100", result.DiffPixels, result.Pixels, len(differing))
+	}
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			c := result.Diff.RGBAAt(x, y)
+			want := c.R == c.G && c.G == c.B
+			for _, p := range differing {
+				if p == image.Pt(x, y) {
+					want = c == red
+				}
+			}
+			if !want {
+				t.Errorf("diff at (%d, %d) is %v", x, y, c)
+			}
+		}
+	}
+}
+
+func TestCompareIgnore(t *testing.T) {
+	expected := uniform(image.Rect(0, 0, 10, 10), color.White)
+	actual := uniform(image.Rect(0, 0, 10, 10), color.White)
+	draw.Draw(actual, image.Rect(2, 2, 5, 5), image.NewUniform(red), image.Point{}, draw.Src)
+	actual.Set(8, 8, red)
+	result, err := Compare(actual, expected, Options{Ignore: []image.Rectangle{image.Rect(2, 2, 5, 5), image.Rect(20, 20, 30, 30)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.DiffPixels != 1 || result.Pixels != 91 {
+		t.Errorf("%d of %d pixels differ, want 1 of 91", result.DiffPixels, result.Pixels)
+	}
+	if c := result.Diff.RGBAAt(3, 3); c.B != 0xff || c.R == 0xff {
+		t.Errorf("diff of ignored pixel is %v, want blue", c)
+	}
+	if c := result.Diff.RGBAAt(8, 8); c != red {
+		t.Errorf("diff of differing pixel is %v, want red", c)
+	}
+}
+
+func TestCompareMetrics(t *testing.T) {
+	base := color.RGBA{0x20, 0x20, 0x80, 0xff}
+	tests := []struct {
+		name   string
+		actual color.RGBA
+		opts   Options
+		differ bool
+	}{
+		{"channel within tolerance", color.RGBA{0x23, 0x1e, 0x80, 0xff}, Options{Tolerance: 3}, false},
+		{"channel above tolerance", color.RGBA{0x24, 0x20, 0x80, 0xff}, Options{Tolerance: 3}, true},
+		{"alpha above tolerance", color.RGBA{0x20, 0x20, 0x80, 0xf0}, Options{Tolerance: 3}, true},
+		{"exact", color.RGBA{0x20, 0x20, 0x81, 0xff}, Options{}, true},
+		// One step of dark blue is hardly visible, but a large step of green is.
+		{"delta E small", color.RGBA{0x20, 0x20, 0x81, 0xff}, Options{Metric: DeltaE}, false},
+		{"delta E large", color.RGBA{0x20, 0x30, 0x80, 0xff}, Options{Metric: DeltaE}, true},
+		{"delta E threshold", color.RGBA{0x20, 0x30, 0x80, 0xff}, Options{Metric: DeltaE, DeltaE: 50}, false},
+	}
+	for _, tt := range tests {
+		expected := uniform(image.Rect(0, 0, 1, 1), base)
+		actual := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+		actual.Set(0, 0, color.NRGBA(tt.actual))
+		result, err := Compare(actual, expected, tt.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (result.DiffPixels == 1) != tt.differ {
+			t.Errorf("%s: DiffPixels is %d (delta E %.2f), want differ %v", tt.name, result.DiffPixels, result.MaxDeltaE, tt.differ)
+		}
+	}
+}
+
+func TestCompareSizeDiffers(t *testing.T) {
+	if _, err := Compare(image.NewRGBA(image.Rect(0, 0, 10, 10)), image.NewRGBA(image.Rect(0, 0, 10, 11)), Options{}); err == nil {
+		t.Error("images of different sizes were compared")
+	}
+}
+
+func TestDeltaE(t *testing.T) {
+	tests := []struct {
+		a, b color.NRGBA
+		want float64
+	}{
+		{color.NRGBA{0, 0, 0, 0xff}, color.NRGBA{0, 0, 0, 0xff}, 0},
+		{color.NRGBA{0, 0, 0, 0xff}, color.NRGBA{0xff, 0xff, 0xff, 0xff}, 100},
+		// Reference values of sRGB red and blue are L*a*b* (53.24, 80.09, 67.20) and (32.30, 79.19, -107.86).
+		{color.NRGBA{0xff, 0, 0, 0xff}, color.NRGBA{0, 0, 0xff, 0xff}, 176.31},
+	}
+	for _, tt := range tests {
+		if got := deltaE(tt.a, tt.b); math.Abs(got-tt.want) > 0.1 {
+			t.Errorf("deltaE(%v, %v) = %.2f, want %.2f", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Package visualtest provides assertions comparing screenshots with golden images, for visual regression tests.
+//
+// Golden images are created or replaced by running tests with the
+// -update-goldens flag, or with ROBOT_UPDATE_GOLDENS=1 in the environment.
+package visualtest
+
+import (
+	"flag"
+	"fmt"
+	"github.com/kbinani/robot"
+	"image"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update-goldens", false, "update golden images of visualtest instead of comparing")
+
+// Options controls comparison with golden images.
+type Options struct {
+	Metric Metric
+	// Tolerance is the largest difference in each channel for the Channel metric, from 0 to 255.
+	Tolerance int
+	// DeltaE is the largest color difference for the DeltaE metric. Default is 2.3, about the smallest difference noticeable.
+	DeltaE float64
+	// MaxDiffPixels is the number of differing pixels allowed.
+	MaxDiffPixels int
+	// MaxDiffRatio is the fraction of differing pixels allowed, from 0 to 1.
+	MaxDiffRatio float64
+	// Ignore lists areas not compared, such as clocks and animations,
+	// relative to the top-left corner of the compared region.
+	Ignore []image.Rectangle
+	// OutputDir is where the actual, expected and diff images of failed
+	// assertions are written. Default is the directory of the golden image.
+	OutputDir string
+}
+
+func (opts Options) withDefaults() Options {
+	if opts.DeltaE <= 0 {
+		opts.DeltaE = 2.3
+	}
+	return opts
+}
+
+// AssertMatchesGolden captures region of the screen and compares it with the golden image at goldenPath.
+func AssertMatchesGolden(t testing.TB, region image.Rectangle, goldenPath string, opts Options) {
+	t.Helper()
+	img, err := robot.Capture(region)
+	if err != nil {
+		t.Fatalf("visualtest: cannot capture %v: %v", region, err)
+	}
+	AssertImageMatchesGolden(t, img, goldenPath, opts)
+}
+
+// AssertImageMatchesGolden compares img with the golden image at goldenPath.
+// On failure, the actual, expected and diff images are written next to the
+// golden image, or to Options.OutputDir.
+func AssertImageMatchesGolden(t testing.TB, img image.Image, goldenPath string, opts Options) {
+	t.Helper()
+	actual := rebase(img)
+	if updating() {
+		if err := writePNG(goldenPath, actual); err != nil {
+			t.Fatalf("visualtest: cannot update golden image: %v", err)
+		}
+		t.Logf("visualtest: updated %s", goldenPath)
+		return
+	}
+	expected, err := readPNG(goldenPath)
+	if os.IsNotExist(err) {
+		t.Fatalf("visualtest: golden image %s does not exist; run tests with -update-goldens to create it", goldenPath)
+	}
+	if err != nil {
+		t.Fatalf("visualtest: cannot read golden image: %v", err)
+	}
+	if actual.Bounds().Size() != expected.Bounds().Size() {
+		paths := writeFailure(t, goldenPath, opts, actual, expected, nil)
+		t.Errorf("visualtest: size %v differs from golden image %s of size %v; %s", actual.Bounds().Size(), goldenPath, expected.Bounds().Size(), paths)
+		return
+	}
+	result, err := Compare(actual, expected, opts)
+	if err != nil {
+		t.Fatalf("visualtest: %v", err)
+	}
+	if result.DiffPixels <= opts.MaxDiffPixels || float64(result.DiffPixels) <= opts.MaxDiffRatio*float64(result.Pixels) {
+		return
+	}
+	paths := writeFailure(t, goldenPath, opts, actual, expected, result.Diff)
+	t.Errorf("visualtest: %d of %d pixels differ from golden image %s (max delta E %.1f); %s",
+		result.DiffPixels, result.Pixels, goldenPath, result.MaxDeltaE, paths)
+}
+
+func updating() bool {
+	return *update || os.Getenv("ROBOT_UPDATE_GOLDENS") == "1"
+}
+
+// writeFailure writes the images of a failed assertion and describes where they are.
+func writeFailure(t testing.TB, goldenPath string, opts Options, actual, expected image.Image, diff image.Image) string {
+	dir := opts.OutputDir
+	if dir == "" {
+		dir = filepath.Dir(goldenPath)
+	}
+	base := strings.TrimSuffix(filepath.Base(goldenPath), filepath.Ext(goldenPath))
+	images := []struct {
+		kind string
+		img  image.Image
+	}{{"actual", actual}, {"expected", expected}, {"diff", diff}}
+	written := []string{}
+	for _, i := range images {
+		if i.img == nil {
+			continue
+		}
+		path := filepath.Join(dir, fmt.Sprintf("%s.%s.png", base, i.kind))
+		if err := writePNG(path, i.img); err != nil {
+			t.Logf("visualtest: cannot write %s: %v", path, err)
+			continue
+		}
+		written = append(written, path)
+	}
+	return "see " + strings.Join(written, ", ")
+}
+
+// rebase returns img moved so that its top-left corner is the origin, as golden images are stored.
+func rebase(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rectangle{Max: b.Size()})
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Src)
+	return dst
+}
+
+func readPNG(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
+
+func writePNG(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
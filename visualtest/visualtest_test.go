@@ -0,0 +1,69 @@
+package visualtest
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// recorder is a testing.TB recording errors instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertImageMatchesGolden(t *testing.T) {
+	dir := t.TempDir()
+	golden := filepath.Join(dir, "golden", "button.png")
+	// The golden image is stored relative to the origin.
+	img := uniform(image.Rect(40, 30, 60, 40), blue)
+	t.Setenv("ROBOT_UPDATE_GOLDENS", "1")
+	AssertImageMatchesGolden(t, img, golden, Options{})
+	t.Setenv("ROBOT_UPDATE_GOLDENS", "")
+	stored, err := readPNG(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Bounds() != image.Rect(0, 0, 20, 10) {
+		t.Fatalf("golden image bounds are %v", stored.Bounds())
+	}
+
+	r := &recorder{TB: t}
+	AssertImageMatchesGolden(r, img, golden, Options{})
+	if len(r.errors) != 0 {
+		t.Errorf("same image failed: %v", r.errors)
+	}
+
+	img.Set(45, 35, red)
+	img.Set(46, 35, red)
+	r = &recorder{TB: t}
+	AssertImageMatchesGolden(r, img, golden, Options{MaxDiffPixels: 2})
+	if len(r.errors) != 0 {
+		t.Errorf("2 differing pixels failed with MaxDiffPixels 2: %v", r.errors)
+	}
+
+	out := filepath.Join(dir, "out")
+	r = &recorder{TB: t}
+	AssertImageMatchesGolden(r, img, golden, Options{MaxDiffPixels: 1, OutputDir: out})
+	if len(r.errors) != 1 {
+		t.Fatalf("errors are %v, want one", r.errors)
+	}
+	for _, kind := range []string{"actual", "expected", "diff"} {
+		if _, err := os.Stat(filepath.Join(out, "button."+kind+".png")); err != nil {
+			t.Error(err)
+		}
+	}
+	diff, err := readPNG(filepath.Join(out, "button.diff.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := diff.At(5, 5); c != red {
+		t.Errorf("diff at (5, 5) is %v, want red", c)
+	}
+}
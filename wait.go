@@ -0,0 +1,142 @@
+package robot
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"time"
+)
+
+// Backoff is a polling strategy. The interval starts at Initial and is
+// multiplied by Factor after each poll, up to Max.
+type Backoff struct {
+	Initial time.Duration
+	Max     time.Duration
+	Factor  float64
+}
+
+// DefaultBackoff returns the polling strategy used by WaitForPixel and WaitForRegionStable.
+func DefaultBackoff() Backoff {
+	return Backoff{
+		Initial: 50 * time.Millisecond,
+		Max:     time.Second,
+		Factor:  1.5,
+	}
+}
+
+// minStableInterval is the shortest polling interval of WaitForRegionStable,
+// which keeps a short quietPeriod from capturing the screen in a busy loop.
+const minStableInterval = 10 * time.Millisecond
+
+// Wait calls cond until it returns true or an error, or ctx is done. It returns ctx.Err() in the last case.
+func (b Backoff) Wait(ctx context.Context, cond func() (bool, error)) error {
+	interval := b.Initial
+	for {
+		ok, err := cond()
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		interval = time.Duration(float64(interval) * b.Factor)
+		if interval > b.Max {
+			interval = b.Max
+		}
+	}
+}
+
+// TimeoutError is returned by the WaitFor functions when the context is done
+// before the screen reaches the expected state.
+type TimeoutError struct {
+	// What describes the awaited state.
+	What string
+	// Last is the last screenshot taken while waiting, which helps to find out why waiting failed.
+	Last *image.RGBA
+	// Err is the error of the context.
+	Err error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("robot: waiting for %s: %v", e.What, e.Err)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// WaitForPixel blocks until the color of the screen at p is within tolerance of c, as in FindColor.
+func WaitForPixel(ctx context.Context, p image.Point, c color.Color, tolerance int) error {
+	var last *image.RGBA
+	err := DefaultBackoff().Wait(ctx, func() (bool, error) {
+		img, err := Capture(image.Rectangle{p, p.Add(image.Pt(1, 1))})
+		if err != nil {
+			return false, err
+		}
+		last = img
+		return len(ColorPoints(img, c, tolerance)) > 0, nil
+	})
+	if err != nil && err == ctx.Err() {
+		return &TimeoutError{What: fmt.Sprintf("color %v at %v", c, p), Last: last, Err: err}
+	}
+	return err
+}
+
+// WaitForRegionStable blocks until region has not changed for quietPeriod, and returns its last screenshot.
+func WaitForRegionStable(ctx context.Context, region image.Rectangle, quietPeriod time.Duration) (*image.RGBA, error) {
+	b := DefaultBackoff()
+	if b.Max > quietPeriod/2 {
+		b.Max = quietPeriod / 2
+	}
+	if b.Max < minStableInterval {
+		b.Max = minStableInterval
+	}
+	if b.Initial > b.Max {
+		b.Initial = b.Max
+	}
+	var last *image.RGBA
+	var since time.Time
+	err := b.Wait(ctx, func() (bool, error) {
+		img, err := Capture(region)
+		if err != nil {
+			return false, err
+		}
+		if last == nil || !SameImage(last, img) {
+			last = img
+			since = time.Now()
+			return false, nil
+		}
+		return time.Since(since) >= quietPeriod, nil
+	})
+	if err != nil && err == ctx.Err() {
+		return nil, &TimeoutError{What: fmt.Sprintf("%v to be stable", region), Last: last, Err: err}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return last, nil
+}
+
+// SameImage reports whether a and b have the same bounds and pixels.
+func SameImage(a, b *image.RGBA) bool {
+	if a.Bounds() != b.Bounds() {
+		return false
+	}
+	r := a.Bounds()
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		ra := a.Pix[a.PixOffset(r.Min.X, y) : a.PixOffset(r.Max.X-1, y)+4]
+		rb := b.Pix[b.PixOffset(r.Min.X, y) : b.PixOffset(r.Max.X-1, y)+4]
+		if string(ra) != string(rb) {
+			return false
+		}
+	}
+	return true
+}
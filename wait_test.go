@@ -0,0 +1,31 @@
+package robot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBackoffWait(t *testing.T) {
+	b := Backoff{Initial: time.Millisecond, Max: 2 * time.Millisecond, Factor: 2}
+	polls := 0
+	err := b.Wait(context.Background(), func() (bool, error) {
+		polls++
+		return polls == 5, nil
+	})
+	if err != nil || polls != 5 {
+		t.Errorf("got %v after %d polls, want nil after 5", err, polls)
+	}
+
+	failed := errors.New("failed")
+	if err := b.Wait(context.Background(), func() (bool, error) { return false, failed }); err != failed {
+		t.Errorf("got %v, want the error of cond", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := b.Wait(ctx, func() (bool, error) { return false, nil }); err != context.DeadlineExceeded {
+		t.Errorf("got %v, want context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,101 @@
+package robot
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"time"
+)
+
+// RegionChange is sent by WatchRegion when the watched region changes.
+type RegionChange struct {
+	Time time.Time
+	// Dirty lists the changed areas in screen coordinates, clipped to the watched region.
+	Dirty []image.Rectangle
+}
+
+// WatchRegion sends changes of rect in screen coordinates to the returned
+// channel, which is closed when ctx is done or watching fails. Changes are
+// reported by the window system where possible, otherwise rect is captured
+// periodically and compared.
+func WatchRegion(ctx context.Context, rect image.Rectangle) (<-chan RegionChange, error) {
+	rect = rect.Canon()
+	if _, err := Capture(rect); err != nil {
+		return nil, err
+	}
+	return watchRegion(ctx, rect)
+}
+
+const (
+	// watchInterval is the capture interval of watchByDiff.
+	watchInterval = 100 * time.Millisecond
+	// watchTile is the size of tiles compared by watchByDiff. Dirty areas are multiples of tiles.
+	watchTile = 16
+)
+
+// watchByDiff implements WatchRegion by comparing captures.
+func watchByDiff(ctx context.Context, rect image.Rectangle) (<-chan RegionChange, error) {
+	prev, err := Capture(rect)
+	if err != nil {
+		return nil, err
+	}
+	c := make(chan RegionChange)
+	go func() {
+		defer close(c)
+		ticker := time.NewTicker(watchInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			img, err := Capture(rect)
+			if err != nil {
+				return
+			}
+			dirty := diffRects(prev, img, watchTile)
+			prev = img
+			if len(dirty) == 0 {
+				continue
+			}
+			select {
+			case c <- RegionChange{Time: time.Now(), Dirty: dirty}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return c, nil
+}
+
+var changedTile = color.RGBA{R: 0xff, A: 0xff}
+
+// diffRects returns the areas where a and b, which have the same bounds,
+// differ. Changed tiles of the given size are merged into the bounding boxes
+// of connected groups.
+func diffRects(a, b *image.RGBA, tile int) []image.Rectangle {
+	bounds := a.Bounds()
+	cols := (bounds.Dx() + tile - 1) / tile
+	rows := (bounds.Dy() + tile - 1) / tile
+	grid := image.NewRGBA(image.Rect(0, 0, cols, rows))
+	changed := false
+	for ty := 0; ty < rows; ty++ {
+		for tx := 0; tx < cols; tx++ {
+			r := image.Rect(tx*tile, ty*tile, (tx+1)*tile, (ty+1)*tile).Add(bounds.Min).Intersect(bounds)
+			if !SameImage(a.SubImage(r).(*image.RGBA), b.SubImage(r).(*image.RGBA)) {
+				grid.SetRGBA(tx, ty, changedTile)
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return nil
+	}
+	result := []image.Rectangle{}
+	for _, box := range ColorBoxes(grid, changedTile, 0) {
+		r := image.Rect(box.Min.X*tile, box.Min.Y*tile, box.Max.X*tile, box.Max.Y*tile)
+		result = append(result, r.Add(bounds.Min).Intersect(bounds))
+	}
+	return result
+}
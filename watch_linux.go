@@ -0,0 +1,84 @@
+package robot
+
+/*
+#cgo LDFLAGS: -lX11 -lXdamage -lXfixes
+#include <poll.h>
+#include <X11/Xlib.h>
+#include <X11/extensions/Xdamage.h>
+
+// next_damage waits up to timeout_ms for damage, and stores at most max damaged rectangles to out.
+static int next_damage(Display *dpy, int event_base, int timeout_ms, XRectangle *out, int max) {
+	if (!XPending(dpy)) {
+		struct pollfd pfd = { ConnectionNumber(dpy), POLLIN, 0 };
+		int ret = poll(&pfd, 1, timeout_ms);
+		if (ret < 0) {
+			return -1;
+		}
+		if (ret == 0) {
+			return 0;
+		}
+	}
+	int n = 0;
+	while (n < max && XPending(dpy)) {
+		XEvent ev;
+		XNextEvent(dpy, &ev);
+		if (ev.type == event_base + XDamageNotify) {
+			out[n++] = ((XDamageNotifyEvent *)&ev)->area;
+		}
+	}
+	return n;
+}
+*/
+import "C"
+
+import (
+	"context"
+	"github.com/kbinani/robot/internal/x11"
+	"image"
+	"time"
+)
+
+func watchRegion(ctx context.Context, rect image.Rectangle) (<-chan RegionChange, error) {
+	// Events are read in a blocking loop, so use a connection separate from the shared one.
+	private, err := x11.OpenPrivate()
+	if err != nil {
+		return watchByDiff(ctx, rect)
+	}
+	dpy := (*C.Display)(private)
+	var eventBase, errorBase C.int
+	if C.XDamageQueryExtension(dpy, &eventBase, &errorBase) == 0 {
+		C.XCloseDisplay(dpy)
+		return watchByDiff(ctx, rect)
+	}
+	damage := C.XDamageCreate(dpy, C.Drawable(C.XDefaultRootWindow(dpy)), C.XDamageReportRawRectangles)
+	C.XFlush(dpy)
+
+	c := make(chan RegionChange)
+	go func() {
+		defer close(c)
+		defer C.XCloseDisplay(dpy)
+		defer C.XDamageDestroy(dpy, damage)
+		areas := make([]C.XRectangle, 64)
+		for ctx.Err() == nil {
+			n := C.next_damage(dpy, eventBase, 100, &areas[0], C.int(len(areas)))
+			if n < 0 {
+				return
+			}
+			dirty := []image.Rectangle{}
+			for _, a := range areas[:n] {
+				r := image.Rect(int(a.x), int(a.y), int(a.x)+int(a.width), int(a.y)+int(a.height)).Intersect(rect)
+				if !r.Empty() {
+					dirty = append(dirty, r)
+				}
+			}
+			if len(dirty) == 0 {
+				continue
+			}
+			select {
+			case c <- RegionChange{Time: time.Now(), Dirty: dirty}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return c, nil
+}
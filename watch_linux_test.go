@@ -0,0 +1,77 @@
+package robot
+
+import (
+	"context"
+	"github.com/kbinani/robot/internal/xvfb"
+	"image"
+	"image/color"
+	"testing"
+	"time"
+)
+
+// expectDirty fills r with c, and waits until the changes received from
+// changes cover r clipped to region.
+func expectDirty(t *testing.T, changes <-chan RegionChange, region, r image.Rectangle, c color.RGBA) {
+	t.Helper()
+	if err := xvfb.Fill(r, c); err != nil {
+		t.Fatal(err)
+	}
+	want := r.Intersect(region)
+	var dirty image.Rectangle
+	timeout := time.After(5 * time.Second)
+	for !want.In(dirty) {
+		select {
+		case change, ok := <-changes:
+			if !ok {
+				t.Fatal("channel closed")
+			}
+			for _, d := range change.Dirty {
+				if !d.In(region) {
+					t.Errorf("dirty area %v is outside of %v", d, region)
+				}
+				dirty = dirty.Union(d)
+			}
+		case <-timeout:
+			t.Fatalf("changes cover %v, want %v", dirty, want)
+		}
+	}
+}
+
+func TestWatchRegion(t *testing.T) {
+	needX(t)
+	region := image.Rect(400, 300, 600, 400)
+	if err := xvfb.Fill(region, color.RGBA{0, 0, 0, 0xff}); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	changes, err := WatchRegion(ctx, region)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectDirty(t, changes, region, image.Rect(420, 320, 450, 340), red)
+	// Changes are clipped to the region.
+	expectDirty(t, changes, region, image.Rect(550, 380, 650, 450), blue)
+
+	cancel()
+	for range changes {
+	}
+}
+
+func TestWatchByDiff(t *testing.T) {
+	needX(t)
+	region := image.Rect(700, 300, 900, 400)
+	if err := xvfb.Fill(region, color.RGBA{0, 0, 0, 0xff}); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	changes, err := watchByDiff(ctx, region)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectDirty(t, changes, region, image.Rect(720, 320, 750, 340), red)
+	expectDirty(t, changes, region, image.Rect(850, 380, 950, 450), blue)
+
+	cancel()
+	for range changes {
+	}
+}
@@ -0,0 +1,43 @@
+package robot
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"reflect"
+	"testing"
+)
+
+func TestDiffRects(t *testing.T) {
+	bounds := image.Rect(100, 50, 150, 90)
+	tests := []struct {
+		name    string
+		changed []image.Point
+		want    []image.Rectangle
+	}{
+		{"unchanged", nil, nil},
+		{"one pixel", []image.Point{{105, 55}}, []image.Rectangle{image.Rect(100, 50, 116, 66)}},
+		{"same tile", []image.Point{{100, 50}, {115, 65}}, []image.Rectangle{image.Rect(100, 50, 116, 66)}},
+		{"adjacent tiles", []image.Point{{115, 55}, {116, 55}}, []image.Rectangle{image.Rect(100, 50, 132, 66)}},
+		{"diagonal tiles", []image.Point{{100, 50}, {120, 70}}, []image.Rectangle{image.Rect(100, 50, 132, 82)}},
+		{
+			"separate tiles",
+			[]image.Point{{100, 50}, {140, 50}},
+			[]image.Rectangle{image.Rect(100, 50, 116, 66), image.Rect(132, 50, 148, 66)},
+		},
+		// Tiles at the right and bottom edges are partial, and clipped to the bounds.
+		{"edge tile", []image.Point{{149, 89}}, []image.Rectangle{image.Rect(148, 82, 150, 90)}},
+	}
+	for _, tt := range tests {
+		a := image.NewRGBA(bounds)
+		draw.Draw(a, bounds, image.NewUniform(color.RGBA{0x10, 0x20, 0x30, 0xff}), image.Point{}, draw.Src)
+		b := image.NewRGBA(bounds)
+		copy(b.Pix, a.Pix)
+		for _, p := range tt.changed {
+			b.SetRGBA(p.X, p.Y, color.RGBA{0x10, 0x20, 0x31, 0xff})
+		}
+		if got := diffRects(a, b, 16); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: diffRects = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package robot
+
+import (
+	"context"
+	"image"
+)
+
+func watchRegion(ctx context.Context, rect image.Rectangle) (<-chan RegionChange, error) {
+	return watchByDiff(ctx, rect)
+}